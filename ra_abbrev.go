@@ -0,0 +1,83 @@
+package ra
+
+import (
+	"slices"
+	"strings"
+)
+
+// expandFlagAbbreviation resolves prefix, an unknown long flag name, to the one
+// visible flag name, alias or --no- form it abbreviates. It returns "" if nothing
+// matches, and an error if the prefix matches more than one flag.
+func (c *Cmd) expandFlagAbbreviation(prefix string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+	var matches []string // full names as typed, one per distinct flag
+	var targets []string // what each match resolves to, to spot aliases of one flag
+	for flagName, flag := range c.flags {
+		base := getBaseFlag(flag)
+		// Name-shadowed global flags are also stored under their short, which isn't
+		// a long name and mustn't be matched
+		if base == nil || base.Name != flagName || base.Hidden || base.PositionalOnly {
+			continue
+		}
+		candidates := append([]string{flagName}, base.visibleAliases()...)
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, prefix) && !slices.Contains(targets, flagName) {
+				matches = append(matches, candidate)
+				targets = append(targets, flagName)
+			}
+		}
+		if f, ok := flag.(*BoolFlag); ok && c.isNegatable(f) {
+			negated := negationPrefix + flagName
+			if strings.HasPrefix(negated, prefix) {
+				matches = append(matches, negated)
+				targets = append(targets, negated)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	default:
+		slices.Sort(matches)
+		return "", newParseError(AmbiguousAbbreviation, prefix, "", "ambiguous flag: --%s, could be %s", prefix, joinOr(matches, "--"))
+	}
+}
+
+// lookupSubCmdAbbreviation finds the one visible subcommand whose name or alias
+// starts with prefix. It returns false if none does, and an error if several do.
+func (c *Cmd) lookupSubCmdAbbreviation(prefix string) (*Cmd, bool, error) {
+	if prefix == "" {
+		return nil, false, nil
+	}
+	var matches []*Cmd
+	for _, subCmd := range c.subCmds {
+		if subCmd.hidden || slices.Contains(matches, subCmd) {
+			continue
+		}
+		for _, name := range append([]string{subCmd.name}, subCmd.visibleAliases()...) {
+			if strings.HasPrefix(name, prefix) {
+				matches = append(matches, subCmd)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		names := make([]string, len(matches))
+		for i, subCmd := range matches {
+			names[i] = subCmd.name
+		}
+		slices.Sort(names)
+		return nil, false, newParseError(AmbiguousAbbreviation, "", prefix, "ambiguous command: %s, could be %s", prefix, joinOr(names, ""))
+	}
+}
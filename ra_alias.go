@@ -0,0 +1,79 @@
+package ra
+
+import (
+	"fmt"
+	"slices"
+)
+
+// resolveFlagAlias returns the name of the flag that name is an alias of, or name
+// itself if it isn't an alias.
+func (c *Cmd) resolveFlagAlias(name string) string {
+	if _, exists := c.flags[name]; exists {
+		return name
+	}
+	for flagName, flag := range c.flags {
+		if base := getBaseFlag(flag); base != nil && slices.Contains(base.Aliases, name) {
+			return flagName
+		}
+	}
+	return name
+}
+
+// validateFlagAliases checks that a flag being registered or inherited doesn't
+// clash, by name or alias, with the names and aliases of flags already on c,
+// including global flags inherited from its parents. A flag of the same name is
+// skipped, as it's being overridden.
+func (c *Cmd) validateFlagAliases(name string, aliases []string) error {
+	for _, alias := range aliases {
+		if alias == "" {
+			return fmt.Errorf("flag %q has an empty alias", name)
+		}
+		if _, exists := c.flags[alias]; exists || alias == name {
+			return fmt.Errorf("alias %q of flag %q conflicts with flag %q", alias, name, alias)
+		}
+	}
+	for flagName, flag := range c.flags {
+		base := getBaseFlag(flag)
+		if base == nil || flagName == name {
+			continue
+		}
+		if slices.Contains(base.Aliases, name) {
+			return fmt.Errorf("flag %q conflicts with alias of flag %q", name, flagName)
+		}
+		for _, alias := range aliases {
+			if slices.Contains(base.Aliases, alias) {
+				return fmt.Errorf("alias %q of flag %q is already an alias of flag %q", alias, name, flagName)
+			}
+		}
+	}
+	return nil
+}
+
+// lookupSubCmd finds a subcommand by name or alias.
+func (c *Cmd) lookupSubCmd(name string) (*Cmd, bool) {
+	if subCmd, exists := c.subCmds[name]; exists {
+		return subCmd, true
+	}
+	for _, subCmd := range c.subCmds {
+		if slices.Contains(subCmd.aliases, name) {
+			return subCmd, true
+		}
+	}
+	return nil, false
+}
+
+// validateSubCmdAliases checks that a subcommand being registered doesn't clash,
+// by name or alias, with the names and aliases of those already registered.
+func (c *Cmd) validateSubCmdAliases(subCmd *Cmd) error {
+	for _, alias := range subCmd.aliases {
+		if alias == "" {
+			return fmt.Errorf("command %q has an empty alias", subCmd.name)
+		}
+	}
+	for _, name := range append([]string{subCmd.name}, subCmd.aliases...) {
+		if existing, exists := c.lookupSubCmd(name); exists {
+			return fmt.Errorf("command %q conflicts with command %q", name, existing.name)
+		}
+	}
+	return nil
+}
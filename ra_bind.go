@@ -0,0 +1,784 @@
+package ra
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind registers a flag for every field of the struct pointed to by opts that
+// carries an `ra` tag, using the field itself as the flag's value pointer. The tag
+// is a comma-separated list of options:
+//
+//	Input  string   `ra:"name=input,short=i,default=json,enum=json|yaml,usage=Input format"`
+//	Tags   []string `ra:"flagonly" sep:","`
+//	Debug  bool     `ra:"global,optional"`
+//
+// Key/value options: name, short, usage, default, enum, regex, min, max (inclusive),
+// sep, env, requires, excludes, type (custom usage type), layout (time fields).
+// List values (enum, requires, excludes, layouts, slice and map defaults) are
+// separated by '|'. Since usage text may contain commas, usage= consumes the rest
+// of the tag; alternatively put it in a separate `usage:"..."` tag. Likewise a
+// separator may be given as `sep:","`.
+//
+// Bare options: optional, flagonly, positionalonly, hidden, hiddeninshort,
+// variadic, global, count (an int counting occurrences, e.g. -vvv) and bytesize
+// (an int64 given as a size, e.g. 10MB).
+//
+// Options that don't apply to the field's type, such as sep on a scalar or min on
+// a string, are errors, like unknown options.
+//
+// Names default to the kebab-cased field name. A nested struct field with an `ra`
+// tag, whose own fields carry `ra` tags, becomes a subcommand, with usage as its
+// description; a bool field inside it tagged `ra:"invoked"` reports whether the
+// subcommand was invoked. Other struct types, except time.Time, are unsupported. Fields are
+// registered in declaration order, which determines positional order.
+func Bind(cmd *Cmd, opts any) error {
+	v := reflect.ValueOf(opts)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind requires a non-nil pointer to a struct, got %T", opts)
+	}
+	return bindStruct(cmd, v.Elem())
+}
+
+type bindTag struct {
+	name           string
+	short          string
+	usage          string
+	def            *string
+	enum           []string
+	regex          string
+	min            string
+	max            string
+	sep            *string
+	env            string
+	requires       []string
+	excludes       []string
+	customType     string
+	layouts        []string
+	optional       bool
+	flagOnly       bool
+	positionalOnly bool
+	hidden         bool
+	hiddenInShort  bool
+	variadic       bool
+	global         bool
+	invoked        bool
+	count          bool
+	byteSize       bool
+}
+
+func parseBindTag(field reflect.StructField, tag string) (bindTag, error) {
+	var t bindTag
+
+	for tag != "" {
+		part := tag
+		rest := ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			part, rest = tag[:idx], tag[idx+1:]
+		}
+
+		key, value, hasValue := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if key == "usage" && hasValue {
+			// usage consumes the rest of the tag so it may contain commas
+			_, t.usage, _ = strings.Cut(tag, "=")
+			break
+		}
+		tag = rest
+
+		if key == "" {
+			continue
+		}
+
+		if hasValue {
+			switch key {
+			case "name":
+				t.name = value
+			case "short":
+				t.short = value
+			case "default":
+				t.def = &value
+			case "enum":
+				t.enum = strings.Split(value, "|")
+			case "regex":
+				t.regex = value
+			case "min":
+				t.min = value
+			case "max":
+				t.max = value
+			case "sep":
+				t.sep = &value
+			case "env":
+				t.env = value
+			case "requires":
+				t.requires = strings.Split(value, "|")
+			case "excludes":
+				t.excludes = strings.Split(value, "|")
+			case "type":
+				t.customType = value
+			case "layout":
+				t.layouts = strings.Split(value, "|")
+			default:
+				return t, fmt.Errorf("field %s: unknown ra tag option %q", field.Name, key)
+			}
+			continue
+		}
+
+		switch key {
+		case "optional":
+			t.optional = true
+		case "flagonly":
+			t.flagOnly = true
+		case "positionalonly":
+			t.positionalOnly = true
+		case "hidden":
+			t.hidden = true
+		case "hiddeninshort":
+			t.hiddenInShort = true
+		case "variadic":
+			t.variadic = true
+		case "global":
+			t.global = true
+		case "invoked":
+			t.invoked = true
+		case "count":
+			t.count = true
+		case "bytesize":
+			t.byteSize = true
+		default:
+			return t, fmt.Errorf("field %s: unknown ra tag option %q", field.Name, key)
+		}
+	}
+
+	if usage, ok := field.Tag.Lookup("usage"); ok {
+		t.usage = usage
+	}
+	if sep, ok := field.Tag.Lookup("sep"); ok {
+		t.sep = &sep
+	}
+	if t.name == "" {
+		t.name = kebabCase(field.Name)
+	}
+	return t, nil
+}
+
+func bindStruct(cmd *Cmd, v reflect.Value) error {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		rawTag, ok := field.Tag.Lookup("ra")
+		if !ok || rawTag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("field %s: cannot bind unexported field", field.Name)
+		}
+
+		tag, err := parseBindTag(field, rawTag)
+		if err != nil {
+			return err
+		}
+		if tag.invoked {
+			// Handled by the enclosing subcommand
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if isSubCmdStruct(field.Type) {
+			if err := bindSubCmd(cmd, fieldValue, tag); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			continue
+		}
+
+		if err := bindFlag(cmd, fieldValue.Addr().Interface(), tag); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// isSubCmdStruct reports whether a field of type typ binds to a subcommand: a
+// struct with tagged fields of its own, rather than a value such as time.Time.
+func isSubCmdStruct(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeFor[time.Time]() {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if _, ok := typ.Field(i).Tag.Lookup("ra"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func bindSubCmd(cmd *Cmd, v reflect.Value, tag bindTag) error {
+	subCmd := NewCmd(tag.name)
+	subCmd.SetDescription(tag.usage)
+	subCmd.SetHidden(tag.hidden)
+	subCmd.SetHiddenInShortHelp(tag.hiddenInShort)
+
+	if err := bindStruct(subCmd, v); err != nil {
+		return err
+	}
+
+	invoked, err := cmd.RegisterCmd(subCmd)
+	if err != nil {
+		return err
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		rawTag, ok := field.Tag.Lookup("ra")
+		if !ok {
+			continue
+		}
+		fieldTag, err := parseBindTag(field, rawTag)
+		if err != nil {
+			return err
+		}
+		if !fieldTag.invoked {
+			continue
+		}
+		ptr, ok := v.Field(i).Addr().Interface().(*bool)
+		if !ok {
+			return fmt.Errorf("field %s: invoked field must be a bool", field.Name)
+		}
+		*ptr = *invoked
+		subCmd.used = ptr
+	}
+	return nil
+}
+
+func bindFlag(cmd *Cmd, ptr any, tag bindTag) error {
+	var regOpts []RegisterOption
+	if tag.global {
+		regOpts = append(regOpts, WithGlobal(true))
+	}
+
+	// These share their Go type with plain numbers, so are chosen by tag
+	if tag.count {
+		p, ok := ptr.(*int)
+		if !ok {
+			return fmt.Errorf("count option requires an int field")
+		}
+		if err := tag.checkTypeOptions("count", "max"); err != nil {
+			return err
+		}
+		return bindCount(cmd, p, tag, regOpts)
+	}
+	if tag.byteSize {
+		p, ok := ptr.(*int64)
+		if !ok {
+			return fmt.Errorf("bytesize option requires an int64 field")
+		}
+		if err := tag.checkTypeOptions("byte size", "min", "max"); err != nil {
+			return err
+		}
+		return bindByteSize(cmd, p, tag, regOpts)
+	}
+	if tag.layouts != nil {
+		if _, ok := ptr.(*time.Time); !ok {
+			return fmt.Errorf("layout option requires a time.Time field")
+		}
+	}
+	if allowed, ok := typeOptions(ptr); ok {
+		if err := tag.checkTypeOptions(reflect.TypeOf(ptr).Elem().String(), allowed...); err != nil {
+			return err
+		}
+	}
+
+	switch p := ptr.(type) {
+	case *string:
+		f := NewString(tag.name)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			f.SetDefault(*tag.def)
+		}
+		f.SetEnumConstraint(tag.enum)
+		if tag.regex != "" {
+			regex, err := regexp.Compile(tag.regex)
+			if err != nil {
+				return fmt.Errorf("invalid regex %q: %w", tag.regex, err)
+			}
+			f.SetRegexConstraint(regex)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *int:
+		f := NewInt(tag.name)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			v, err := strconv.Atoi(*tag.def)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+			}
+			f.SetDefault(v)
+		}
+		if tag.min != "" {
+			v, err := strconv.Atoi(tag.min)
+			if err != nil {
+				return fmt.Errorf("invalid min %q: %w", tag.min, err)
+			}
+			f.SetMin(v, true)
+		}
+		if tag.max != "" {
+			v, err := strconv.Atoi(tag.max)
+			if err != nil {
+				return fmt.Errorf("invalid max %q: %w", tag.max, err)
+			}
+			f.SetMax(v, true)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *int64:
+		f := NewInt64(tag.name)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			v, err := strconv.ParseInt(*tag.def, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+			}
+			f.SetDefault(v)
+		}
+		if tag.min != "" {
+			v, err := strconv.ParseInt(tag.min, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid min %q: %w", tag.min, err)
+			}
+			f.SetMin(v, true)
+		}
+		if tag.max != "" {
+			v, err := strconv.ParseInt(tag.max, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid max %q: %w", tag.max, err)
+			}
+			f.SetMax(v, true)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *float64:
+		f := NewFloat64(tag.name)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			v, err := strconv.ParseFloat(*tag.def, 64)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+			}
+			f.SetDefault(v)
+		}
+		if tag.min != "" {
+			v, err := strconv.ParseFloat(tag.min, 64)
+			if err != nil {
+				return fmt.Errorf("invalid min %q: %w", tag.min, err)
+			}
+			f.SetMin(v, true)
+		}
+		if tag.max != "" {
+			v, err := strconv.ParseFloat(tag.max, 64)
+			if err != nil {
+				return fmt.Errorf("invalid max %q: %w", tag.max, err)
+			}
+			f.SetMax(v, true)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *time.Duration:
+		f := NewDuration(tag.name)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			v, err := time.ParseDuration(*tag.def)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+			}
+			f.SetDefault(v)
+		}
+		if tag.min != "" {
+			v, err := time.ParseDuration(tag.min)
+			if err != nil {
+				return fmt.Errorf("invalid min %q: %w", tag.min, err)
+			}
+			f.SetMin(v, true)
+		}
+		if tag.max != "" {
+			v, err := time.ParseDuration(tag.max)
+			if err != nil {
+				return fmt.Errorf("invalid max %q: %w", tag.max, err)
+			}
+			f.SetMax(v, true)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *time.Time:
+		f := NewTime(tag.name, tag.layouts...)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			v, ok := f.parse(*tag.def)
+			if !ok {
+				return fmt.Errorf("invalid default %q: does not match the time layouts", *tag.def)
+			}
+			f.SetDefault(v)
+		}
+		if tag.min != "" {
+			v, ok := f.parse(tag.min)
+			if !ok {
+				return fmt.Errorf("invalid min %q: does not match the time layouts", tag.min)
+			}
+			f.SetMin(v, true)
+		}
+		if tag.max != "" {
+			v, ok := f.parse(tag.max)
+			if !ok {
+				return fmt.Errorf("invalid max %q: does not match the time layouts", tag.max)
+			}
+			f.SetMax(v, true)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *map[string]string:
+		return bindMap(cmd, p, NewStringMap(tag.name), tag, regOpts)
+	case *map[string]int:
+		return bindMap(cmd, p, NewIntMap(tag.name), tag, regOpts)
+	case *uint:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *uint8:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *uint16:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *uint32:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *uint64:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *int8:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *int16:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *int32:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *float32:
+		return bindNumber(cmd, p, tag, regOpts)
+	case *bool:
+		f := NewBool(tag.name)
+		tag.applyBase(&f.BaseFlag)
+		if tag.def != nil {
+			v, err := strconv.ParseBool(*tag.def)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+			}
+			f.SetDefault(v)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *[]string:
+		f := NewStringSlice(tag.name)
+		tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+		if tag.def != nil {
+			f.SetDefault(strings.Split(*tag.def, "|"))
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *[]int:
+		f := NewIntSlice(tag.name)
+		tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+		if tag.def != nil {
+			v, err := parseBindDefaults(*tag.def, strconv.Atoi)
+			if err != nil {
+				return err
+			}
+			f.SetDefault(v)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *[]int64:
+		f := NewInt64Slice(tag.name)
+		tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+		if tag.def != nil {
+			v, err := parseBindDefaults(*tag.def, func(s string) (int64, error) {
+				return strconv.ParseInt(s, 10, 64)
+			})
+			if err != nil {
+				return err
+			}
+			f.SetDefault(v)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *[]float64:
+		f := NewFloat64Slice(tag.name)
+		tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+		if tag.def != nil {
+			v, err := parseBindDefaults(*tag.def, func(s string) (float64, error) {
+				return strconv.ParseFloat(s, 64)
+			})
+			if err != nil {
+				return err
+			}
+			f.SetDefault(v)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *[]time.Duration:
+		f := NewDurationSlice(tag.name)
+		tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+		if tag.def != nil {
+			v, err := parseBindDefaults(*tag.def, time.ParseDuration)
+			if err != nil {
+				return err
+			}
+			f.SetDefault(v)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	case *[]uint:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]uint8:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]uint16:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]uint32:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]uint64:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]int8:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]int16:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]int32:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]float32:
+		return bindNumberSlice(cmd, p, tag, regOpts)
+	case *[]bool:
+		f := NewBoolSlice(tag.name)
+		tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+		if tag.def != nil {
+			v, err := parseBindDefaults(*tag.def, strconv.ParseBool)
+			if err != nil {
+				return err
+			}
+			f.SetDefault(v)
+		}
+		return f.RegisterWithPtr(cmd, p, regOpts...)
+	}
+
+	return fmt.Errorf("unsupported field type %s", reflect.TypeOf(ptr).Elem())
+}
+
+// bindNumber registers a NumberFlag for the sized numeric types.
+func bindNumber[T number](cmd *Cmd, ptr *T, tag bindTag, regOpts []RegisterOption) error {
+	f := newNumber[T](tag.name)
+	tag.applyBase(&f.BaseFlag)
+	if tag.def != nil {
+		v, err := parseNumber[T](f.Name, *tag.def, false)
+		if err != nil {
+			return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+		}
+		f.SetDefault(v)
+	}
+	if tag.min != "" {
+		v, err := parseNumber[T](f.Name, tag.min, false)
+		if err != nil {
+			return fmt.Errorf("invalid min %q: %w", tag.min, err)
+		}
+		f.SetMin(v, true)
+	}
+	if tag.max != "" {
+		v, err := parseNumber[T](f.Name, tag.max, false)
+		if err != nil {
+			return fmt.Errorf("invalid max %q: %w", tag.max, err)
+		}
+		f.SetMax(v, true)
+	}
+	return f.RegisterWithPtr(cmd, ptr, regOpts...)
+}
+
+// bindCount registers a CountFlag for an int field tagged count.
+func bindCount(cmd *Cmd, ptr *int, tag bindTag, regOpts []RegisterOption) error {
+	f := NewCount(tag.name)
+	tag.applyBase(&f.BaseFlag)
+	if tag.def != nil {
+		v, err := strconv.Atoi(*tag.def)
+		if err != nil {
+			return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+		}
+		f.SetDefault(v)
+	}
+	if tag.max != "" {
+		v, err := strconv.Atoi(tag.max)
+		if err != nil {
+			return fmt.Errorf("invalid max %q: %w", tag.max, err)
+		}
+		f.SetMax(v)
+	}
+	return f.RegisterWithPtr(cmd, ptr, regOpts...)
+}
+
+// bindByteSize registers a ByteSizeFlag for an int64 field tagged bytesize.
+func bindByteSize(cmd *Cmd, ptr *int64, tag bindTag, regOpts []RegisterOption) error {
+	f := NewByteSize(tag.name)
+	tag.applyBase(&f.BaseFlag)
+	if tag.def != nil {
+		v, err := parseByteSize(*tag.def)
+		if err != nil {
+			return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+		}
+		f.SetDefault(v)
+	}
+	if tag.min != "" {
+		v, err := parseByteSize(tag.min)
+		if err != nil {
+			return fmt.Errorf("invalid min %q: %w", tag.min, err)
+		}
+		f.SetMin(v, true)
+	}
+	if tag.max != "" {
+		v, err := parseByteSize(tag.max)
+		if err != nil {
+			return fmt.Errorf("invalid max %q: %w", tag.max, err)
+		}
+		f.SetMax(v, true)
+	}
+	return f.RegisterWithPtr(cmd, ptr, regOpts...)
+}
+
+// bindMap registers f, a MapFlag, for a map field. enum restricts the keys, and a
+// default is given as key=value entries, e.g. default=env=dev|team=core.
+func bindMap[V any](cmd *Cmd, ptr *map[string]V, f *MapFlag[V], tag bindTag, regOpts []RegisterOption) error {
+	tag.applyBase(&f.BaseFlag)
+	if tag.sep != nil {
+		f.Separator = tag.sep
+	}
+	if tag.enum != nil {
+		f.SetKeyEnumConstraint(tag.enum)
+	}
+	if tag.def != nil {
+		def := make(map[string]V)
+		for _, entry := range strings.Split(*tag.def, "|") {
+			key, rawVal, found := strings.Cut(entry, "=")
+			if !found || key == "" {
+				return fmt.Errorf("invalid default %q: expected key=value entries", *tag.def)
+			}
+			v, err := f.parse(rawVal)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %w", *tag.def, err)
+			}
+			def[key] = v
+		}
+		f.SetDefault(def)
+	}
+	return f.RegisterWithPtr(cmd, ptr, regOpts...)
+}
+
+// bindNumberSlice registers a NumberSliceFlag for slices of the sized numeric types.
+func bindNumberSlice[T number](cmd *Cmd, ptr *[]T, tag bindTag, regOpts []RegisterOption) error {
+	f := newNumberSlice[T](tag.name)
+	tag.applySliceBase(&f.BaseFlag, &f.Separator, &f.Variadic)
+	if tag.def != nil {
+		v, err := parseBindDefaults(*tag.def, func(s string) (T, error) {
+			return parseNumber[T](f.Name, s, false)
+		})
+		if err != nil {
+			return err
+		}
+		f.SetDefault(v)
+	}
+	return f.RegisterWithPtr(cmd, ptr, regOpts...)
+}
+
+// typeOptions returns the type-specific tag options that apply to a field bound
+// through ptr, or false if the field's type isn't supported.
+func typeOptions(ptr any) ([]string, bool) {
+	switch ptr.(type) {
+	case *string:
+		return []string{"enum", "regex"}, true
+	case *bool:
+		return nil, true
+	case *time.Time:
+		return []string{"min", "max", "layout"}, true
+	case *map[string]string, *map[string]int:
+		return []string{"enum", "sep"}, true
+	}
+	switch typ := reflect.TypeOf(ptr).Elem(); typ.Kind() {
+	case reflect.Slice:
+		return []string{"sep", "variadic"}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return []string{"min", "max"}, true
+	}
+	return nil, false
+}
+
+// checkTypeOptions rejects type-specific options set in the tag other than those
+// allowed for the field's flag type, described by kind.
+func (t bindTag) checkTypeOptions(kind string, allowed ...string) error {
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{"enum", t.enum != nil},
+		{"regex", t.regex != ""},
+		{"min", t.min != ""},
+		{"max", t.max != ""},
+		{"sep", t.sep != nil},
+		{"variadic", t.variadic},
+		{"layout", t.layouts != nil},
+	}
+	for _, opt := range options {
+		if opt.set && !slices.Contains(allowed, opt.name) {
+			return fmt.Errorf("%s option is not supported for %s fields", opt.name, kind)
+		}
+	}
+	return nil
+}
+
+// applyBase copies the options shared by all flag types onto base.
+func (t bindTag) applyBase(base *BaseFlag) {
+	base.Short = t.short
+	base.Usage = t.usage
+	base.CustomUsageType = t.customType
+	base.Optional = t.optional
+	base.Hidden = t.hidden
+	base.HiddenInShortHelp = t.hiddenInShort
+	base.PositionalOnly = t.positionalOnly
+	base.FlagOnly = t.flagOnly
+	base.Env = t.env
+	if t.requires != nil {
+		base.Requires = &t.requires
+	}
+	if t.excludes != nil {
+		base.Excludes = &t.excludes
+	}
+}
+
+// applySliceBase copies the shared and slice-specific options onto a slice flag.
+func (t bindTag) applySliceBase(base *BaseFlag, separator **string, variadic *bool) {
+	t.applyBase(base)
+	*separator = t.sep
+	*variadic = t.variadic
+}
+
+func parseBindDefaults[T any](def string, parse func(string) (T, error)) ([]T, error) {
+	parts := strings.Split(def, "|")
+	values := make([]T, 0, len(parts))
+	for _, part := range parts {
+		v, err := parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default %q: %w", def, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// kebabCase converts a Go field name to a flag name, e.g. "DryRun" -> "dry-run"
+// and "HTTPPort" -> "http-port".
+func kebabCase(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				sb.WriteByte('-')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
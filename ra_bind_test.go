@@ -0,0 +1,305 @@
+package ra
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bind_RegistersAndParsesFields(t *testing.T) {
+	var opts struct {
+		Input   string   `ra:"short=i,usage=Input file, relative to cwd"`
+		Format  string   `ra:"default=json,enum=json|yaml,flagonly"`
+		Count   int      `ra:"short=n,default=1,min=1,max=10,flagonly"`
+		Size    int64    `ra:"optional,flagonly"`
+		Ratio   float64  `ra:"default=0.5,flagonly"`
+		DryRun  bool     `ra:"flagonly"`
+		Tags    []string `ra:"optional,flagonly" sep:","`
+		Ports   []int    `ra:"default=80|443,flagonly"`
+		Ignored string
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	err := cmd.ParseOrError([]string{
+		"in.txt", "--format", "yaml", "-n", "3", "--size", "1024", "--dry-run", "--tags", "a,b",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "in.txt", opts.Input)
+	assert.Equal(t, "yaml", opts.Format)
+	assert.Equal(t, 3, opts.Count)
+	assert.Equal(t, int64(1024), opts.Size)
+	assert.Equal(t, 0.5, opts.Ratio)
+	assert.True(t, opts.DryRun)
+	assert.Equal(t, []string{"a", "b"}, opts.Tags)
+	assert.Equal(t, []int{80, 443}, opts.Ports)
+	assert.Equal(t, "", opts.Ignored)
+	assert.Equal(t, "Input file, relative to cwd", getBaseFlag(cmd.flags["input"]).Usage)
+}
+
+func Test_Bind_EnforcesConstraints(t *testing.T) {
+	var opts struct {
+		Format string `ra:"enum=json|yaml"`
+		Count  int    `ra:"max=10,optional,flagonly"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	err := cmd.ParseOrError([]string{"csv"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid 'format' value: csv")
+
+	cmd = NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+	err = cmd.ParseOrError([]string{"json", "--count", "11"})
+	assert.EqualError(t, err, "'count' value 11 is > maximum 10")
+}
+
+func Test_Bind_NamesAndUsageTag(t *testing.T) {
+	var opts struct {
+		HTTPPort   int    `ra:"optional" usage:"Port, for HTTP"`
+		OutputDir  string `ra:"name=out,optional"`
+		MaxRetries int    `ra:"optional,env=APP_RETRIES,requires=http-port"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	assert.Equal(t, []string{"http-port", "out", "max-retries"}, cmd.positional)
+	assert.Equal(t, "Port, for HTTP", getBaseFlag(cmd.flags["http-port"]).Usage)
+	assert.Equal(t, "APP_RETRIES", getBaseFlag(cmd.flags["max-retries"]).Env)
+	assert.Equal(t, []string{"http-port"}, *getBaseFlag(cmd.flags["max-retries"]).Requires)
+}
+
+func Test_Bind_NestedStructsBecomeSubcommands(t *testing.T) {
+	var opts struct {
+		Verbose bool `ra:"short=v,global"`
+		Deploy  struct {
+			Invoked  bool   `ra:"invoked"`
+			Target   string `ra:""`
+			Replicas int    `ra:"default=1,flagonly"`
+		} `ra:"usage=Deploy the app"`
+		Status struct {
+			Invoked bool `ra:"invoked"`
+		} `ra:""`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	err := cmd.ParseOrError([]string{"deploy", "prod", "--replicas", "3", "-v"})
+	assert.NoError(t, err)
+	assert.True(t, opts.Deploy.Invoked)
+	assert.False(t, opts.Status.Invoked)
+	assert.Equal(t, "prod", opts.Deploy.Target)
+	assert.Equal(t, 3, opts.Deploy.Replicas)
+	assert.True(t, opts.Verbose)
+	assert.Equal(t, "Deploy the app", cmd.subCmds["deploy"].description)
+}
+
+func Test_Bind_Errors(t *testing.T) {
+	cmd := NewCmd("app")
+
+	var notStruct string
+	assert.EqualError(t, Bind(cmd, &notStruct), "bind requires a non-nil pointer to a struct, got *string")
+	assert.Error(t, Bind(cmd, struct{}{}))
+
+	var unknownOpt struct {
+		Name string `ra:"flagonyl"`
+	}
+	assert.EqualError(t, Bind(cmd, &unknownOpt), `field Name: unknown ra tag option "flagonyl"`)
+
+	var badDefault struct {
+		Count int `ra:"default=abc"`
+	}
+	err := Bind(cmd, &badDefault)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), `field Count: invalid default "abc"`))
+
+	var unsupported struct {
+		Values map[string]float64 `ra:""`
+	}
+	assert.EqualError(t, Bind(cmd, &unsupported), "field Values: unsupported field type map[string]float64")
+}
+
+func Test_Bind_TagOptions(t *testing.T) {
+	var spaced struct {
+		Name string `ra:"optional, flagonly, usage=Name to greet, or everyone"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &spaced))
+	assert.Equal(t, "Name to greet, or everyone", cmd.flags["name"].(*StringFlag).Usage)
+
+	// Options that don't apply to the field's type are rejected, not dropped
+	var layoutOnInt struct {
+		Port int `ra:"layout=2006-01-02"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &layoutOnInt), "field Port: layout option requires a time.Time field")
+	var sepOnScalar struct {
+		Name string `ra:"sep=,"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &sepOnScalar), "field Name: sep option is not supported for string fields")
+	var sepTagOnScalar struct {
+		Port int `ra:"" sep:","`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &sepTagOnScalar), "field Port: sep option is not supported for int fields")
+	var minOnString struct {
+		Name string `ra:"min=1"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &minOnString), "field Name: min option is not supported for string fields")
+	var enumOnSlice struct {
+		Tags []string `ra:"enum=a|b"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &enumOnSlice), "field Tags: enum option is not supported for []string fields")
+	var regexOnDuration struct {
+		Wait time.Duration `ra:"regex=^1"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &regexOnDuration), "field Wait: regex option is not supported for time.Duration fields")
+	var variadicOnMap struct {
+		Labels map[string]string `ra:"variadic"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &variadicOnMap), "field Labels: variadic option is not supported for map[string]string fields")
+	var minOnCount struct {
+		Verbose int `ra:"count,min=1"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &minOnCount), "field Verbose: min option is not supported for count fields")
+}
+
+func Test_Bind_KebabCase(t *testing.T) {
+	assert.Equal(t, "dry-run", kebabCase("DryRun"))
+	assert.Equal(t, "http-port", kebabCase("HTTPPort"))
+	assert.Equal(t, "input", kebabCase("Input"))
+	assert.Equal(t, "v2-api", kebabCase("V2API"))
+}
+
+func Test_Bind_Durations(t *testing.T) {
+	var opts struct {
+		Timeout time.Duration   `ra:"default=30s,min=1s,max=1h,flagonly"`
+		Backoff []time.Duration `ra:"default=1s|5s,flagonly"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	err := cmd.ParseOrError([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, opts.Timeout)
+	assert.Equal(t, []time.Duration{time.Second, 5 * time.Second}, opts.Backoff)
+
+	cmd = NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+	err = cmd.ParseOrError([]string{"--timeout", "2h"})
+	assert.EqualError(t, err, "'timeout' value 2h is > maximum 1h")
+}
+
+func Test_Bind_SizedNumbers(t *testing.T) {
+	var opts struct {
+		Port  uint16  `ra:"default=8080,min=1,flagonly"`
+		Mode  uint32  `ra:"optional,flagonly"`
+		Ratio float32 `ra:"default=0.5,flagonly"`
+		Masks []uint8 `ra:"default=1|2,flagonly"`
+		Skew  []int16 `ra:"optional,flagonly" sep:","`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	err := cmd.ParseOrError([]string{"--mode", "493", "--skew", "-1,1"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(8080), opts.Port)
+	assert.Equal(t, uint32(493), opts.Mode)
+	assert.Equal(t, float32(0.5), opts.Ratio)
+	assert.Equal(t, []uint8{1, 2}, opts.Masks)
+	assert.Equal(t, []int16{-1, 1}, opts.Skew)
+
+	var bad struct {
+		Port uint16 `ra:"default=70000"`
+	}
+	err = Bind(NewCmd("app"), &bad)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), `field Port: invalid default "70000"`))
+}
+
+func Test_Bind_ByteSize(t *testing.T) {
+	var opts struct {
+		Limit int64 `ra:"bytesize,default=1MB,max=1GB,flagonly"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+	assert.IsType(t, &ByteSizeFlag{}, cmd.flags["limit"])
+
+	err := cmd.ParseOrError([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000*1000), opts.Limit)
+
+	cmd = NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+	err = cmd.ParseOrError([]string{"--limit", "2GB"})
+	assert.Error(t, err)
+
+	var badSize struct {
+		Limit int `ra:"bytesize"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &badSize), "field Limit: bytesize option requires an int64 field")
+}
+
+func Test_Bind_Maps(t *testing.T) {
+	var opts struct {
+		Labels  map[string]string `ra:"optional,flagonly,enum=env|team"`
+		Weights map[string]int    `ra:"default=a=1|b=2,flagonly"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+
+	err := cmd.ParseOrError([]string{"--labels", "env=prod,team=core"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, opts.Labels)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, opts.Weights)
+
+	var badDefault struct {
+		Weights map[string]int `ra:"default=a"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &badDefault), `field Weights: invalid default "a": expected key=value entries`)
+}
+
+func Test_Bind_Count(t *testing.T) {
+	var opts struct {
+		Verbose int `ra:"short=v,count,max=3"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+	assert.IsType(t, &CountFlag{}, cmd.flags["verbose"])
+
+	err := cmd.ParseOrError([]string{"-vv"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, opts.Verbose)
+
+	var bad struct {
+		Verbose string `ra:"count"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &bad), "field Verbose: count option requires an int field")
+}
+
+func Test_Bind_Time(t *testing.T) {
+	var opts struct {
+		Since time.Time `ra:"optional,flagonly"`
+		Until time.Time `ra:"layout=2006-01-02,default=2030-01-01,flagonly"`
+	}
+	cmd := NewCmd("app")
+	assert.NoError(t, Bind(cmd, &opts))
+	assert.IsType(t, &TimeFlag{}, cmd.flags["since"])
+	assert.Empty(t, cmd.subCmds)
+
+	err := cmd.ParseOrError([]string{"--since", "2024-05-01T10:00:00Z"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), opts.Since.UTC())
+	assert.Equal(t, "2030-01-01", opts.Until.Format("2006-01-02"))
+
+	// A struct that isn't a subcommand is rejected rather than guessed at
+	var other struct {
+		Window struct{ From, To int } `ra:"optional"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &other), "field Window: unsupported field type struct { From int; To int }")
+	var badLayout struct {
+		Day string `ra:"layout=2006-01-02"`
+	}
+	assert.EqualError(t, Bind(NewCmd("app"), &badLayout), "field Day: layout option requires a time.Time field")
+}
@@ -0,0 +1,111 @@
+package ra
+
+import (
+	"fmt"
+)
+
+// bounds holds optional min/max constraints for flag types, with the same
+// semantics as IntFlag.SetMin/SetMax (inclusive unless stated otherwise).
+type bounds[T any] struct {
+	compare      func(a, b T) int // e.g. cmp.Compare
+	min          *T
+	max          *T
+	minInclusive *bool
+	maxInclusive *bool
+}
+
+func (b *bounds[T]) setMin(min T, inclusive bool) {
+	b.min = &min
+	b.minInclusive = &inclusive
+}
+
+func (b *bounds[T]) setMax(max T, inclusive bool) {
+	b.max = &max
+	b.maxInclusive = &inclusive
+}
+
+func (b *bounds[T]) isSet() bool {
+	return b.min != nil || b.max != nil
+}
+
+// check returns an error like IntFlag.validateDefaultValue when val is out of range,
+// rendering values with format. Parse errors prefix it with the quoted flag name.
+func (b *bounds[T]) check(val T, format func(T) string) error {
+	if b.min != nil {
+		inclusive := b.minInclusive == nil || *b.minInclusive // default to inclusive
+		if (inclusive && b.compare(val, *b.min) < 0) || (!inclusive && b.compare(val, *b.min) <= 0) {
+			if inclusive {
+				return fmt.Errorf("value %s is < minimum %s", format(val), format(*b.min))
+			} else {
+				return fmt.Errorf("value %s is <= minimum (exclusive) %s", format(val), format(*b.min))
+			}
+		}
+	}
+
+	if b.max != nil {
+		inclusive := b.maxInclusive == nil || *b.maxInclusive // default to inclusive
+		if (inclusive && b.compare(val, *b.max) > 0) || (!inclusive && b.compare(val, *b.max) >= 0) {
+			if inclusive {
+				return fmt.Errorf("value %s is > maximum %s", format(val), format(*b.max))
+			} else {
+				return fmt.Errorf("value %s is >= maximum (exclusive) %s", format(val), format(*b.max))
+			}
+		}
+	}
+
+	return nil
+}
+
+// rangeString renders the bounds for help output like getRangeString, e.g. "[1s, 1m)".
+func (b *bounds[T]) rangeString(format func(T) string) string {
+	if !b.isSet() {
+		return ""
+	}
+
+	left := "("
+	if b.min != nil {
+		if b.minInclusive == nil || *b.minInclusive {
+			left = "[" + format(*b.min)
+		} else {
+			left = "(" + format(*b.min)
+		}
+	}
+
+	right := ")"
+	if b.max != nil {
+		if b.maxInclusive == nil || *b.maxInclusive {
+			right = format(*b.max) + "]"
+		} else {
+			right = format(*b.max) + ")"
+		}
+	}
+
+	return left + ", " + right
+}
+
+// dumpString renders the bounds for dump output like getFlagTypeForDump, e.g. "[1s,+∞)".
+func (b *bounds[T]) dumpString(format func(T) string) string {
+	if !b.isSet() {
+		return ""
+	}
+
+	minStr := "(-∞"
+	if b.min != nil {
+		if b.minInclusive != nil && !*b.minInclusive {
+			minStr = "(" + format(*b.min)
+		} else {
+			minStr = "[" + format(*b.min)
+		}
+	}
+
+	maxStr := "+∞)"
+	if b.max != nil {
+		if b.maxInclusive != nil && !*b.maxInclusive {
+			maxStr = format(*b.max) + ")"
+		} else {
+			maxStr = format(*b.max) + "]"
+		}
+	}
+
+	return fmt.Sprintf("%s,%s", minStr, maxStr)
+}
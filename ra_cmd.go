@@ -1,7 +1,10 @@
 package ra
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
 )
 
 type UsageHeaders struct {
@@ -23,11 +26,21 @@ func DefaultUsageHeaders() UsageHeaders {
 }
 
 type ParseHooks struct {
-	PostParse func(cmd *Cmd, err error) // Called after parsing, before any output
+	PostParse  func(cmd *Cmd, err error)      // Called after parsing, before any output
+	Deprecated func(cmd *Cmd, warning string) // Called for each deprecated flag or command used, instead of warning on stderr
+
+	// Called by Execute around the invoked command's handler, with cmd being that
+	// command. Hooks set on it and each of its parents run: PreRun outermost first,
+	// then PostRun innermost first if the handler succeeded. An error stops the run.
+	PreRun  func(ctx context.Context, cmd *Cmd) error
+	PostRun func(ctx context.Context, cmd *Cmd) error
 }
 
 type Cmd struct {
 	name                  string
+	aliases               []string          // alternative names the command can be invoked by
+	deprecated            string            // if set, invoking the command warns with this message
+	deprecatedAliases     map[string]string // aliases that warn when used, mapped to their messages
 	description           string
 	flags                 map[string]any  // flag name -> flag itself (either a Flag[T] or SliceFlag[T])
 	positional            []string        // positional flags, i.e. flags that are positional args
@@ -38,10 +51,14 @@ type Cmd struct {
 	shadowedNameFlags     map[string]bool // global flags that lost their name to non-global flags (name collisions)
 	subCmds               map[string]*Cmd
 	shortToName           map[string]string // short flag -> full name mapping
+	flagGroups            []FlagGroup       // constraints across several flags, e.g. exactly one of them
 
 	// completion
 	completionEnabled bool // if true, __complete subcommand is recognized
 
+	// execution
+	run func(ctx context.Context, cmd *Cmd) error // handler Execute runs when this is the deepest command invoked
+
 	// options
 	customUsage       func(bool)    // if set, this function will be called to print usage instead of the default
 	parseHooks        *ParseHooks   // if set, hooks will be called after parsing
@@ -50,13 +67,25 @@ type Cmd struct {
 	hiddenInShortHelp bool          // if true, hide from short help (-h), show in long help (--help)
 	autoHelpOnNoArgs  bool          // if true, show help when no args provided and required args exist
 	usageHeaders      *UsageHeaders // custom headers for usage output
+	allowIntPrefixes  *bool         // default for integer flags' SetAllowPrefixes; nil inherits the parent's
+	negatableBools    *bool         // default for bool flags' SetNegatable; nil inherits the parent's
+	suggestions       *bool         // whether errors suggest similar names; nil inherits the parent's
+	suggestDist       *int          // max edit distance for suggestions; nil inherits the parent's
 
 	// state post-parse
-	used             *bool           // after parsing, whether this command was invoked
-	configured       map[string]bool // specified flags from flags.
-	unknownArgs      []string        // unknown args when ignoreUnknown is true
-	lastVariadicFlag string          // last variadic flag that was used
-	sawFlag          bool            // true if we've seen a flag since the last variadic
+	used             *bool                   // after parsing, whether this command was invoked
+	configured       map[string]bool         // specified flags from flags.
+	sources          map[string]*ValueSource // where each configured flag's value came from
+	pendingSources   []string                // flags configured by the arg currently being parsed
+	unknownArgs      []string                // unknown args when ignoreUnknown is true
+	lastVariadicFlag string                  // last variadic flag that was used
+	sawFlag          bool                    // true if we've seen a flag since the last variadic
+	intPrefixes      bool                    // resolved default for prefixed integer literals
+	parentNegatable  bool                    // negatable bools default inherited from the parent command
+	suggest          bool                    // resolved setting for "did you mean" suggestions
+	suggestMax       int                     // resolved max edit distance for suggestions
+	deprecations     []string                // deprecation warnings not yet reported
+	ctx              context.Context         // context of the parse, from ExecuteContext
 }
 
 func NewCmd(name string) *Cmd {
@@ -69,6 +98,7 @@ func NewCmd(name string) *Cmd {
 		shadowedNameFlags:     make(map[string]bool),
 		subCmds:               make(map[string]*Cmd),
 		configured:            make(map[string]bool),
+		sources:               make(map[string]*ValueSource),
 		helpEnabled:           true,
 		shortToName:           make(map[string]string),
 	}
@@ -81,6 +111,33 @@ func (c *Cmd) SetDescription(desc string) *Cmd {
 	return c
 }
 
+// SetAliases sets alternative names the command can be invoked by as a subcommand,
+// e.g. "rm" and "del" for "remove".
+func (c *Cmd) SetAliases(aliases ...string) *Cmd {
+	c.aliases = aliases
+	return c
+}
+
+// SetDeprecated marks the command deprecated: invoking it warns with msg (e.g.
+// "use 'remove' instead"), and it's hidden from short help.
+func (c *Cmd) SetDeprecated(msg string) *Cmd {
+	c.deprecated = msg
+	return c
+}
+
+// SetDeprecatedAlias adds alias as an alternative name that warns with msg when
+// used, e.g. a command's old name after a rename. It's not shown in help.
+func (c *Cmd) SetDeprecatedAlias(alias, msg string) *Cmd {
+	if !slices.Contains(c.aliases, alias) {
+		c.aliases = append(c.aliases, alias)
+	}
+	if c.deprecatedAliases == nil {
+		c.deprecatedAliases = make(map[string]string)
+	}
+	c.deprecatedAliases[alias] = msg
+	return c
+}
+
 func (c *Cmd) SetCustomUsage(fn func(isLongHelp bool)) *Cmd {
 	c.customUsage = fn
 	return c
@@ -91,6 +148,12 @@ func (c *Cmd) SetParseHooks(hooks *ParseHooks) *Cmd {
 	return c
 }
 
+// SetRun sets the handler Execute runs when this is the deepest command invoked.
+func (c *Cmd) SetRun(run func(ctx context.Context, cmd *Cmd) error) *Cmd {
+	c.run = run
+	return c
+}
+
 func (c *Cmd) SetHelpEnabled(enable bool) *Cmd {
 	c.helpEnabled = enable
 	return c
@@ -111,6 +174,34 @@ func (c *Cmd) SetAutoHelpOnNoArgs(enable bool) *Cmd {
 	return c
 }
 
+// SetAllowIntPrefixes sets the default for integer flags of this command and its
+// subcommands that don't call SetAllowPrefixes themselves.
+func (c *Cmd) SetAllowIntPrefixes(allow bool) *Cmd {
+	c.allowIntPrefixes = &allow
+	return c
+}
+
+// SetNegatableBools sets the default for bool flags of this command and its
+// subcommands that don't call SetNegatable themselves.
+func (c *Cmd) SetNegatableBools(negatable bool) *Cmd {
+	c.negatableBools = &negatable
+	return c
+}
+
+// SetSuggestions sets whether errors for unknown flags, subcommands and enum values
+// of this command and its subcommands suggest similar names. Enabled by default.
+func (c *Cmd) SetSuggestions(enable bool) *Cmd {
+	c.suggestions = &enable
+	return c
+}
+
+// SetSuggestionDistance sets the furthest edit distance (default 2) at which names
+// are suggested for this command and its subcommands. Below 1 disables suggestions.
+func (c *Cmd) SetSuggestionDistance(max int) *Cmd {
+	c.suggestDist = &max
+	return c
+}
+
 func (c *Cmd) SetUsageHeaders(headers UsageHeaders) *Cmd {
 	c.usageHeaders = &headers
 	return c
@@ -128,20 +219,24 @@ func (c *Cmd) getUsageHeaders() UsageHeaders {
 }
 
 func (c *Cmd) applyGlobalFlags(subCmd *Cmd) error {
-	// Apply global flags
+	// Check the names and aliases of inherited global flags don't clash with the
+	// aliases of the subcommand's flags before adding any
 	for _, globalFlagName := range c.globalFlags {
-		var flag any
-		var exists bool
-
-		// Check if we have an overridden version (original with short intact)
-		if overriddenFlag, overriddenExists := c.overriddenGlobalFlags[globalFlagName]; overriddenExists {
-			flag = overriddenFlag
-			exists = true
-		} else {
-			flag, exists = c.flags[globalFlagName]
+		if _, exists := subCmd.flags[globalFlagName]; exists {
+			continue
 		}
+		if base := getBaseFlag(c.globalFlag(globalFlagName)); base != nil {
+			if err := subCmd.validateFlagAliases(base.Name, base.Aliases); err != nil {
+				return fmt.Errorf("global flag %q of command %q: %w", globalFlagName, c.name, err)
+			}
+		}
+	}
+
+	// Apply global flags
+	for _, globalFlagName := range c.globalFlags {
+		flag := c.globalFlag(globalFlagName)
 
-		if exists {
+		if flag != nil {
 			// Only add flag if it doesn't already exist in subcommand
 			if _, exists := subCmd.flags[globalFlagName]; !exists {
 				subCmd.flags[globalFlagName] = flag
@@ -161,6 +256,16 @@ func (c *Cmd) applyGlobalFlags(subCmd *Cmd) error {
 	return nil
 }
 
+// globalFlag returns the global flag of c named name as subcommands inherit it,
+// or nil if there's none.
+func (c *Cmd) globalFlag(name string) any {
+	// Check if we have an overridden version (original with short intact)
+	if overriddenFlag, exists := c.overriddenGlobalFlags[name]; exists {
+		return overriddenFlag
+	}
+	return c.flags[name]
+}
+
 // ResetParseState resets all parsing-related state to a clean slate, allowing the command
 // to be parsed again from scratch.
 //
@@ -171,9 +276,12 @@ func (c *Cmd) ResetParseState() {
 		*c.used = false
 	}
 	c.configured = make(map[string]bool)
+	c.sources = make(map[string]*ValueSource)
+	c.pendingSources = nil
 	c.unknownArgs = []string{}
 	c.lastVariadicFlag = ""
 	c.sawFlag = false
+	c.ctx = nil
 
 	// Reset all flag values to their defaults
 	_ = c.setDefaults()
@@ -187,10 +295,48 @@ func (c *Cmd) ResetParseState() {
 	}
 }
 
-// Whether a flag was explicitly configured by the user.
+// clone copies c and its subcommands for a parse that mustn't touch them, as in
+// Parse: flags get Value pointers of their own and parse state starts afresh.
+// Only what parsing changes is copied; the rest, e.g. usage, is shared.
+func (c *Cmd) clone(clones map[any]any) *Cmd {
+	clone := *c
+	clone.flags = make(map[string]any, len(c.flags))
+	for name, flag := range c.flags {
+		clone.flags[name] = cloneFlag(flag, clones)
+	}
+	clone.overriddenGlobalFlags = make(map[string]any, len(c.overriddenGlobalFlags))
+	for name, flag := range c.overriddenGlobalFlags {
+		clone.overriddenGlobalFlags[name] = cloneFlag(flag, clones)
+	}
+	clone.positional = slices.Clone(c.positional)
+	clone.nonPositional = slices.Clone(c.nonPositional)
+	clone.globalFlags = slices.Clone(c.globalFlags)
+	clone.shadowedShortFlags = maps.Clone(c.shadowedShortFlags)
+	clone.shadowedNameFlags = maps.Clone(c.shadowedNameFlags)
+	clone.shortToName = maps.Clone(c.shortToName)
+	clone.subCmds = make(map[string]*Cmd, len(c.subCmds))
+	for name, subCmd := range c.subCmds {
+		clone.subCmds[name] = subCmd.clone(clones)
+	}
+
+	if c.used != nil {
+		clone.used = new(bool)
+	}
+	clone.configured = make(map[string]bool)
+	clone.sources = make(map[string]*ValueSource)
+	clone.pendingSources = nil
+	clone.unknownArgs = []string{}
+	clone.lastVariadicFlag = ""
+	clone.sawFlag = false
+	clone.deprecations = nil
+	clone.ctx = nil
+	return &clone
+}
+
+// Whether a flag was explicitly configured by the user. name may be an alias.
 func (c *Cmd) Configured(name string) bool {
 	// Check if flag is configured in this command
-	if configured, exists := c.configured[name]; exists && configured {
+	if configured, exists := c.configured[c.resolveFlagAlias(name)]; exists && configured {
 		return true
 	}
 
@@ -206,6 +352,15 @@ func (c *Cmd) Configured(name string) bool {
 	return false
 }
 
+// Context returns the context passed to ExecuteContext for the last parse, e.g. for
+// use in hooks, or context.Background() if there is none.
+func (c *Cmd) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 func (c *Cmd) GetUnknownArgs() []string {
 	return c.unknownArgs
 }
@@ -214,15 +369,18 @@ func (c *Cmd) RegisterCmd(subCmd *Cmd) (*bool, error) {
 	if _, exists := c.subCmds[subCmd.name]; exists {
 		return nil, fmt.Errorf("command %q already defined", subCmd.name)
 	}
-
-	c.subCmds[subCmd.name] = subCmd
-	subCmd.used = new(bool)
+	if err := c.validateSubCmdAliases(subCmd); err != nil {
+		return nil, err
+	}
 
 	// Apply global flags to subcommand for usage generation
 	if err := c.applyGlobalFlags(subCmd); err != nil {
 		return nil, err
 	}
 
+	c.subCmds[subCmd.name] = subCmd
+	subCmd.used = new(bool)
+
 	return subCmd.used, nil
 }
 
@@ -253,6 +411,10 @@ func (c *Cmd) validatePositionalOnlyAfterVariadic(flagName string) error {
 			if f.Variadic {
 				return fmt.Errorf("cannot register positional-only flag %q after variadic positional flag %q (positional-only flags cannot be set after variadic flags)", flagName, existingName)
 			}
+		case valueFlag:
+			if f.isVariadic() {
+				return fmt.Errorf("cannot register positional-only flag %q after variadic positional flag %q (positional-only flags cannot be set after variadic flags)", flagName, existingName)
+			}
 		}
 	}
 	return nil
@@ -92,6 +92,13 @@ func NewProgrammingError(msg string) *ProgrammingError {
 }
 
 func (c *Cmd) ParseOrExit(args []string, opts ...ParseOpt) {
+	c.parseOrExit(args, opts...)
+}
+
+// parseOrExit is ParseOrExit, returning whether parsing succeeded and the
+// program should carry on.
+func (c *Cmd) parseOrExit(args []string, opts ...ParseOpt) bool {
+	initializeColorFromEnv()
 	err := c.parse(args, opts...)
 
 	// Call PostParse hook after parsing, before any output (success or error)
@@ -103,7 +110,7 @@ func (c *Cmd) ParseOrExit(args []string, opts ...ParseOpt) {
 		// Check if this is a completion invoked error (output already written)
 		if _, ok := err.(*completionInvokedError); ok {
 			osExit(0)
-			return
+			return false
 		}
 
 		// Check if this is a help invoked error
@@ -157,9 +164,17 @@ func (c *Cmd) ParseOrExit(args []string, opts ...ParseOpt) {
 			osExit(1)
 		}
 	}
+	return err == nil
 }
 
 func (c *Cmd) ParseOrError(args []string, opts ...ParseOpt) error {
+	initializeColorFromEnv()
+	return c.parseOrError(args, opts...)
+}
+
+// parseOrError is ParseOrError without applying RA_COLOR, which sets global state
+// and so is kept out of Parse.
+func (c *Cmd) parseOrError(args []string, opts ...ParseOpt) error {
 	err := c.parse(args, opts...)
 
 	// Call PostParse hook after parsing, before any output (success or error)
@@ -200,8 +215,11 @@ func (c *Cmd) parse(args []string, opts ...ParseOpt) error {
 }
 
 func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opts ...ParseOpt) error {
-	initializeColorFromEnv()
+	// Errors not tied to an argument are still tied to this command
+	return c.located(c.parseArgs(args, preserveConfigured, opts...), -1)
+}
 
+func (c *Cmd) parseArgs(args []string, preserveConfigured bool, opts ...ParseOpt) error {
 	cfg := &parseCfg{}
 	for _, opt := range opts {
 		opt(cfg)
@@ -210,10 +228,20 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 	// reset state in case this is called multiple times
 	if !preserveConfigured {
 		c.configured = make(map[string]bool)
+		c.sources = make(map[string]*ValueSource)
+	}
+	c.pendingSources = nil
+	c.resolveIntPrefixes(cfg)
+	c.parentNegatable = cfg.negatableBools
+	c.resolveSuggestions(cfg)
+	c.deprecations = nil
+	if cfg.deprecationHook == nil && c.parseHooks != nil {
+		cfg.deprecationHook = c.parseHooks.Deprecated
 	}
 	c.unknownArgs = []string{}
 	c.lastVariadicFlag = ""
 	c.sawFlag = false
+	c.ctx = cfg.ctx
 
 	// Add help flags if enabled
 	if c.helpEnabled {
@@ -222,6 +250,7 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 				"help",
 			).SetShort("h").
 				SetUsage("Print usage string.").
+				SetNegatable(false).
 				SetOptional(true).
 				Register(c, WithGlobal(true))
 		}
@@ -283,29 +312,70 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 			if err := c.assignPositionalWithMode(arg, true); err != nil {
 				if cfg.ignoreUnknown {
 					c.unknownArgs = append(c.unknownArgs, arg)
-				} else {
+				} else if err := cfg.report(c.located(err, cfg.argOffset+i)); err != nil {
 					return err
 				}
 			}
+			c.recordArgSources(SourcePositional, args[i:i+1], cfg.argOffset+i)
 			i++
 			continue
 		}
 
 		// Check for subcommand first (only if not in positional-only mode)
 		if !strings.HasPrefix(arg, "-") {
-			if subCmd, exists := c.subCmds[arg]; exists {
+			subCmd, exists := c.lookupSubCmd(arg)
+			invokedAs := arg
+			if !exists && cfg.subCmdAbbreviations {
+				var err error
+				if subCmd, exists, err = c.lookupSubCmdAbbreviation(arg); err != nil {
+					return cfg.abort(c.located(err, cfg.argOffset+i))
+				}
+				if exists {
+					invokedAs = subCmd.name
+				}
+			}
+			if exists {
 				*subCmd.used = true
 				// Apply global flags to subcommand before parsing
 				if err := c.applyGlobalFlags(subCmd); err != nil {
-					return err
+					return cfg.abort(err)
 				}
 				// Apply global configured state before parsing
 				for _, globalFlagName := range c.globalFlags {
 					if c.configured[globalFlagName] {
 						subCmd.configured[globalFlagName] = true
+						if source, exists := c.sources[globalFlagName]; exists {
+							subCmd.sources[globalFlagName] = source
+						}
 					}
 				}
-				return subCmd.parseWithPreserveState(args[i+1:], true, opts...)
+				// Hand the subcommand its section of the config
+				if err := c.loadConfig(cfg); err != nil {
+					return cfg.abort(err)
+				}
+				// Warn about what was used before the subcommand, and the subcommand itself
+				c.noteDeprecatedSubCmd(subCmd, invokedAs)
+				if err := cfg.report(c.reportDeprecations(cfg)); err != nil {
+					return err
+				}
+				subOpts := append(append([]ParseOpt{}, opts...), withConfigSection(
+					c.configSectionFor(cfg.configSection, subCmd),
+					joinConfigKey(cfg.configKeyPrefix, subCmd.name),
+				), withArgOffset(cfg.argOffset+i+1), withIntPrefixes(c.intPrefixes),
+					withNegatableBools(c.negatableDefault()), withDeprecationHook(cfg.deprecationHook),
+					withSuggestions(c.suggest, c.suggestMax))
+				if err := cfg.report(subCmd.parseWithPreserveState(args[i+1:], true, subOpts...)); err != nil {
+					return err
+				}
+				// Global flags were resolved by the subcommand, which shares them;
+				// only this command's own flags are left to fill from env and config.
+				if err := cfg.report(c.applyEnvValues(c.isGlobalFlag)); err != nil {
+					return err
+				}
+				if err := cfg.report(c.applyConfigValues(cfg.configSection, cfg.configKeyPrefix, c.isGlobalFlag)); err != nil {
+					return err
+				}
+				return cfg.collected()
 			}
 		}
 
@@ -313,15 +383,17 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 		if strings.HasPrefix(arg, "-") {
 			consumed, err := c.parseFlag(args, i, numberShortsMode, cfg)
 			if err != nil {
-				if err.Error() == "not a flag: "+arg {
+				err = c.located(err, cfg.argOffset+i)
+				if errors.Is(err, errNotAFlag) {
 					// This is a negative number, treat as positional
 					if err := c.assignPositional(arg); err != nil {
 						if cfg.ignoreUnknown {
 							c.unknownArgs = append(c.unknownArgs, arg)
-						} else {
+						} else if err := cfg.report(c.located(err, cfg.argOffset+i)); err != nil {
 							return err
 						}
 					}
+					c.recordArgSources(SourcePositional, args[i:i+1], cfg.argOffset+i)
 					i++
 					continue
 				}
@@ -344,13 +416,14 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 							// If variadic assignment fails, fall back to normal unknown handling
 							if cfg.ignoreUnknown {
 								c.unknownArgs = append(c.unknownArgs, arg)
-							} else {
+							} else if err := cfg.report(c.located(err, cfg.argOffset+i)); err != nil {
 								return err
 							}
 						} else {
 							// Successfully assigned - activate the variadic if it wasn't already
 							c.lastVariadicFlag = variadicFlag
 						}
+						c.recordArgSources(SourcePositional, args[i:i+1], cfg.argOffset+i)
 						i++
 						continue
 					}
@@ -358,11 +431,19 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 
 				if cfg.ignoreUnknown {
 					c.unknownArgs = append(c.unknownArgs, arg)
+					c.pendingSources = nil
 					i++
 					continue
 				}
-				return err
+				if err := cfg.report(err); err != nil {
+					return err
+				}
+				// Collecting errors: skip what the flag consumed and carry on
+				c.pendingSources = nil
+				i += max(consumed, 1)
+				continue
 			}
+			c.recordArgSources(SourceFlag, args[i:i+consumed], cfg.argOffset+i)
 			c.sawFlag = true
 			c.lastVariadicFlag = "" // Reset variadic state when we see a flag
 			i += consumed
@@ -371,10 +452,17 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 			if err := c.assignPositional(arg); err != nil {
 				if cfg.ignoreUnknown {
 					c.unknownArgs = append(c.unknownArgs, arg)
-				} else {
+				} else if suggestions := c.suggestSubCmds(arg); len(suggestions) > 0 {
+					// Likely a mistyped subcommand rather than a stray positional
+					err := newParseError(UnknownSubcommand, "", arg, "unknown command: %s%s", arg, didYouMean(suggestions, ""))
+					if err := cfg.report(c.located(err, cfg.argOffset+i)); err != nil {
+						return err
+					}
+				} else if err := cfg.report(c.located(err, cfg.argOffset+i)); err != nil {
 					return err
 				}
 			}
+			c.recordArgSources(SourcePositional, args[i:i+1], cfg.argOffset+i)
 			i++
 		}
 	}
@@ -405,8 +493,27 @@ func (c *Cmd) parseWithPreserveState(args []string, preserveConfigured bool, opt
 		}
 	}
 
+	if err := cfg.report(c.reportDeprecations(cfg)); err != nil {
+		return err
+	}
+
+	// Fill flags not given on the command line from their environment variables,
+	// then from the config
+	if err := cfg.report(c.applyEnvValues(nil)); err != nil {
+		return err
+	}
+	if err := c.loadConfig(cfg); err != nil {
+		return cfg.abort(err)
+	}
+	if err := cfg.report(c.applyConfigValues(cfg.configSection, cfg.configKeyPrefix, nil)); err != nil {
+		return err
+	}
+
 	// Validate required flags
-	return c.validateRequired()
+	if err := c.validateRequired(cfg); err != nil {
+		return err
+	}
+	return cfg.collected()
 }
 
 func (c *Cmd) setDefaults() error {
@@ -472,6 +579,10 @@ func (c *Cmd) setDefaults() error {
 					*f.Value = []bool{}
 				}
 			}
+		case valueFlag:
+			if !c.configured[f.getBase().Name] {
+				f.applyDefault()
+			}
 		}
 	}
 	return nil
@@ -500,6 +611,8 @@ func (c *Cmd) hasNumberShorts() bool {
 			short = f.Short
 		case *BoolSliceFlag:
 			short = f.Short
+		case valueFlag:
+			short = f.getBase().Short
 		}
 		if short != "" && len(short) == 1 && isDigit(short[0]) {
 			return true
@@ -541,6 +654,10 @@ func (c *Cmd) findNextUnassignedVariadicPositional() string {
 			if f.Variadic && !f.FlagOnly {
 				return name
 			}
+		case valueFlag:
+			if f.isVariadic() && !f.getBase().FlagOnly {
+				return name
+			}
 		}
 	}
 	return ""
@@ -557,7 +674,7 @@ func (c *Cmd) parseFlag(args []string, index int, numberShortsMode bool, cfg *pa
 		return c.parseShortFlag(args, index, numberShortsMode, cfg)
 	}
 
-	return 0, fmt.Errorf("invalid flag: %s", arg)
+	return 0, newParseError(UnknownFlag, "", arg, "invalid flag: %s", arg)
 }
 
 func (c *Cmd) parseLongFlag(args []string, index int, cfg *parseCfg) (int, error) {
@@ -573,36 +690,67 @@ func (c *Cmd) parseLongFlag(args []string, index int, cfg *parseCfg) (int, error
 		hasValue = true
 	}
 
+	if name := c.resolveFlagAlias(flagName); name != flagName {
+		c.noteDeprecatedFlagAlias(flagName, name)
+		flagName = name
+	}
 	flag, exists := c.flags[flagName]
+	if _, negated := c.lookupNegatedFlag(flagName); !exists && !negated && cfg.abbreviations {
+		full, err := c.expandFlagAbbreviation(flagName)
+		if err != nil {
+			if c.helpEnabled && c.hasHelpFlags(args) {
+				return 0, c.createHelpError(args)
+			}
+			return 0, err
+		}
+		if full != "" {
+			flagName = c.resolveFlagAlias(full)
+			flag, exists = c.flags[flagName]
+		}
+	}
 	if !exists {
+		if f, ok := c.lookupNegatedFlag(flagName); ok {
+			if hasValue {
+				return 0, newParseError(InvalidValue, flagName, value, "flag --%s does not take a value", flagName)
+			}
+			c.markConfigured(f.Name)
+			return 1, c.setBoolValue(f, false)
+		}
 		// Before returning unknown flag error, check if help flags are present
 		if c.helpEnabled && c.hasHelpFlags(args) {
 			return 0, c.createHelpError(args)
 		}
-		return 0, fmt.Errorf("unknown flag: --%s", flagName)
+		return 0, newParseError(UnknownFlag, flagName, "", "unknown flag: --%s%s", flagName, didYouMean(c.suggestFlags(flagName), "--"))
 	}
 
-	c.configured[flagName] = true
+	c.markConfigured(flagName)
 
 	switch f := flag.(type) {
 	case *BoolFlag:
 		if hasValue {
 			val, err := c.parseBoolValue(value)
 			if err != nil {
-				return 0, fmt.Errorf("invalid value for flag --%s: %s", flagName, err.Error())
+				return 0, newParseError(InvalidValue, flagName, value, "invalid value for flag --%s: %s", flagName, err.Error())
 			}
-			*f.Value = val
-		} else {
-			*f.Value = true
+			return 1, c.setBoolValue(f, val)
+		}
+		return 1, c.setBoolValue(f, true)
+	case *CountFlag:
+		if hasValue {
+			return 1, c.setValue(f, value)
 		}
-		return 1, nil
+		return 1, f.increment()
 	case *StringFlag:
 		if hasValue {
 			err := c.setStringValue(f, value)
 			return 1, err
 		}
+		if f.noOptDefault != nil {
+			*f.Value = *f.noOptDefault
+			return 1, nil
+		}
 		if index+1 >= len(args) {
-			return 0, fmt.Errorf("flag --%s requires a value", flagName)
+			return 0, newParseError(MissingValue, flagName, "", "flag --%s requires a value", flagName)
 		}
 		err := c.setStringValue(f, args[index+1])
 		return 2, err
@@ -611,8 +759,12 @@ func (c *Cmd) parseLongFlag(args []string, index int, cfg *parseCfg) (int, error
 			err := c.setIntValue(f, value)
 			return 1, err
 		}
+		if f.noOptDefault != nil {
+			*f.Value = *f.noOptDefault
+			return 1, nil
+		}
 		if index+1 >= len(args) {
-			return 0, fmt.Errorf("flag --%s requires a value", flagName)
+			return 0, newParseError(MissingValue, flagName, "", "flag --%s requires a value", flagName)
 		}
 		err := c.setIntValue(f, args[index+1])
 		return 2, err
@@ -622,7 +774,7 @@ func (c *Cmd) parseLongFlag(args []string, index int, cfg *parseCfg) (int, error
 			return 1, err
 		}
 		if index+1 >= len(args) {
-			return 0, fmt.Errorf("flag --%s requires a value", flagName)
+			return 0, newParseError(MissingValue, flagName, "", "flag --%s requires a value", flagName)
 		}
 		err := c.setInt64Value(f, args[index+1])
 		return 2, err
@@ -632,7 +784,7 @@ func (c *Cmd) parseLongFlag(args []string, index int, cfg *parseCfg) (int, error
 			return 1, err
 		}
 		if index+1 >= len(args) {
-			return 0, fmt.Errorf("flag --%s requires a value", flagName)
+			return 0, newParseError(MissingValue, flagName, "", "flag --%s requires a value", flagName)
 		}
 		err := c.setFloat64Value(f, args[index+1])
 		return 2, err
@@ -673,6 +825,26 @@ func (c *Cmd) parseLongFlag(args []string, index int, cfg *parseCfg) (int, error
 			return 1, err
 		}
 		return c.parseBoolSliceFlag(args, index, f)
+	case valueFlag:
+		if hasValue {
+			err := c.setValue(f, value)
+			if err == nil && f.isVariadic() {
+				c.lastVariadicFlag = flagName
+			}
+			return 1, err
+		}
+		if f.isSlice() {
+			consumed, err := c.parseValueSliceFlag(args, index, f, cfg)
+			if err == nil && f.isVariadic() {
+				c.lastVariadicFlag = flagName
+			}
+			return consumed, err
+		}
+		if index+1 >= len(args) {
+			return 0, newParseError(MissingValue, flagName, "", "flag --%s requires a value", flagName)
+		}
+		err := c.setValue(f, args[index+1])
+		return 2, err
 	}
 
 	return 0, NewProgrammingError(fmt.Sprintf("unsupported flag type for: %s", flagName))
@@ -694,7 +866,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 	// Check if this is a negative number without number shorts mode
 	if !numberShortsMode && len(shorts) > 0 && (isDigit(shorts[0]) || shorts[0] == '.') {
 		// This is a negative number, treat as positional
-		return 0, fmt.Errorf("not a flag: %s", arg)
+		return 0, errNotAFlag
 	}
 
 	// In number shorts mode, check if this is a negative number
@@ -702,7 +874,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 		// This is a number short flag
 		if flagName, exists := c.shortToName[shorts]; exists {
 			flag := c.flags[flagName]
-			c.configured[flagName] = true
+			c.markConfigured(flagName)
 
 			switch f := flag.(type) {
 			case *IntFlag:
@@ -761,7 +933,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 				} else {
 					// Use next argument
 					if index+1 >= len(args) {
-						return 0, fmt.Errorf("flag -%s requires a value", shorts)
+						return 0, newParseError(MissingValue, flagName, "", "flag -%s requires a value", shorts)
 					}
 					err := c.setStringValue(f, args[index+1])
 					return 2, err
@@ -777,20 +949,19 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 			shortStr := string(shorts[0])
 			flagName, exists := c.shortToName[shortStr]
 			if !exists {
-				return 0, fmt.Errorf("unknown shorthand flag: -%s", shortStr)
+				return 0, newParseError(UnknownFlag, shortStr, "", "unknown shorthand flag: -%s", shortStr)
 			}
 
 			flag := c.flags[flagName]
-			c.configured[flagName] = true
+			c.markConfigured(flagName)
 
 			switch f := flag.(type) {
 			case *BoolFlag:
 				val, err := c.parseBoolValue(value)
 				if err != nil {
-					return 0, fmt.Errorf("invalid value for flag -%s: %s", shortStr, err.Error())
+					return 0, newParseError(InvalidValue, flagName, value, "invalid value for flag -%s: %s", shortStr, err.Error())
 				}
-				*f.Value = val
-				return 1, nil
+				return 1, c.setBoolValue(f, val)
 			case *StringFlag:
 				err := c.setStringValue(f, value)
 				return 1, err
@@ -821,6 +992,12 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 			case *BoolSliceFlag:
 				_, err := c.appendBoolSliceValue(f, value)
 				return 1, err
+			case valueFlag:
+				err := c.setValue(f, value)
+				if err == nil && f.isVariadic() {
+					c.lastVariadicFlag = flagName
+				}
+				return 1, err
 			}
 
 			return 0, NewProgrammingError(fmt.Sprintf("unsupported flag type for: %s", flagName))
@@ -847,7 +1024,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 			if flagName, exists := c.shortToName[string(firstChar)]; exists {
 				if flag, exists := c.flags[flagName]; exists {
 					if intFlag, ok := flag.(*IntFlag); ok {
-						c.configured[flagName] = true
+						c.markConfigured(flagName)
 						if hasValue {
 							// Explicit equals value takes precedence over counting
 							err := c.setIntValue(intFlag, value)
@@ -859,7 +1036,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 						}
 					}
 					if int64Flag, ok := flag.(*Int64Flag); ok {
-						c.configured[flagName] = true
+						c.markConfigured(flagName)
 						if hasValue {
 							// Explicit equals value takes precedence over counting
 							err := c.setInt64Value(int64Flag, value)
@@ -879,15 +1056,26 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 		shortStr := string(short)
 		flagName, exists := c.shortToName[shortStr]
 		if !exists {
-			return 0, fmt.Errorf("unknown shorthand flag: '%s' in -%s", shortStr, shortStr)
+			return 0, newParseError(UnknownFlag, shortStr, "", "unknown shorthand flag: '%s' in -%s", shortStr, shortStr)
 		}
 
 		flag := c.flags[flagName]
-		c.configured[flagName] = true
+		c.markConfigured(flagName)
 
 		switch f := flag.(type) {
 		case *BoolFlag:
-			*f.Value = true
+			if err := c.setBoolValue(f, true); err != nil {
+				return 0, err
+			}
+		case *CountFlag:
+			if i == len(shorts)-1 && hasValue {
+				// Explicit equals value takes precedence over counting
+				if err := c.setValue(f, value); err != nil {
+					return 0, err
+				}
+			} else if err := f.increment(); err != nil {
+				return 0, err
+			}
 		case *StringFlag:
 			if i == len(shorts)-1 {
 				// Last flag in cluster, can take value
@@ -898,10 +1086,14 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 						return 0, err
 					}
 					consumed = 1
+				} else if f.noOptDefault != nil {
+					// Value is optional, never taken from the next argument
+					*f.Value = *f.noOptDefault
+					consumed = 1
 				} else {
 					// Use next argument
 					if index+1 >= len(args) {
-						return 0, fmt.Errorf("flag -%s requires a value", shortStr)
+						return 0, newParseError(MissingValue, flagName, "", "flag -%s requires a value", shortStr)
 					}
 					err := c.setStringValue(f, args[index+1])
 					if err != nil {
@@ -910,7 +1102,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					consumed = 2
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *IntFlag:
 			if i == len(shorts)-1 {
@@ -922,6 +1114,10 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 						return 0, err
 					}
 					consumed = 1
+				} else if f.noOptDefault != nil {
+					// Value is optional, never taken from the next argument
+					*f.Value = *f.noOptDefault
+					consumed = 1
 				} else {
 					// Check if next argument exists and is a valid value (not a flag)
 					if index+1 < len(args) && !strings.HasPrefix(args[index+1], "-") {
@@ -938,7 +1134,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					}
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *StringSliceFlag:
 			if i == len(shorts)-1 {
@@ -965,7 +1161,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					return consumed, nil
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *Int64Flag:
 			if i == len(shorts)-1 {
@@ -993,7 +1189,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					}
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *Float64Flag:
 			if i == len(shorts)-1 {
@@ -1008,7 +1204,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 				} else {
 					// Use next argument
 					if index+1 >= len(args) {
-						return 0, fmt.Errorf("flag -%s requires a value", shortStr)
+						return 0, newParseError(MissingValue, flagName, "", "flag -%s requires a value", shortStr)
 					}
 					err := c.setFloat64Value(f, args[index+1])
 					if err != nil {
@@ -1017,7 +1213,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					consumed = 2
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *IntSliceFlag:
 			if i == len(shorts)-1 {
@@ -1038,7 +1234,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					return consumed, nil
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *Int64SliceFlag:
 			if i == len(shorts)-1 {
@@ -1059,7 +1255,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					return consumed, nil
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *Float64SliceFlag:
 			if i == len(shorts)-1 {
@@ -1080,7 +1276,7 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					return consumed, nil
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		case *BoolSliceFlag:
 			if i == len(shorts)-1 {
@@ -1101,7 +1297,42 @@ func (c *Cmd) parseShortFlag(args []string, index int, numberShortsMode bool, cf
 					return consumed, nil
 				}
 			} else {
-				return 0, fmt.Errorf("non-bool flag -%s must be last in cluster", shortStr)
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
+			}
+		case valueFlag:
+			if i == len(shorts)-1 {
+				// Last flag in cluster, can take value
+				if hasValue {
+					// Use equals value
+					if err := c.setValue(f, value); err != nil {
+						return 0, err
+					}
+					consumed = 1
+				} else if f.isSlice() {
+					// Use parseValueSliceFlag for next argument(s)
+					consumed, err := c.parseValueSliceFlag(args, index, f, cfg)
+					if err != nil {
+						return 0, err
+					}
+					if f.isVariadic() {
+						c.lastVariadicFlag = flagName
+					}
+					return consumed, nil
+				} else {
+					// Use next argument
+					if index+1 >= len(args) {
+						return 0, newParseError(MissingValue, flagName, "", "flag -%s requires a value", shortStr)
+					}
+					if err := c.setValue(f, args[index+1]); err != nil {
+						return 0, err
+					}
+					consumed = 2
+				}
+				if f.isVariadic() {
+					c.lastVariadicFlag = flagName
+				}
+			} else {
+				return 0, newParseError(MissingValue, flagName, "", "non-bool flag -%s must be last in cluster", shortStr)
 			}
 		}
 	}
@@ -1126,7 +1357,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			return c.setStringValue(f, value)
 		case *IntFlag:
 			if f.FlagOnly {
@@ -1135,7 +1366,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			return c.setIntValue(f, value)
 		case *Int64Flag:
 			if f.FlagOnly {
@@ -1144,7 +1375,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			return c.setInt64Value(f, value)
 		case *Float64Flag:
 			if f.FlagOnly {
@@ -1153,7 +1384,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			return c.setFloat64Value(f, value)
 		case *BoolFlag:
 			if f.FlagOnly {
@@ -1162,13 +1393,12 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			val, err := strconv.ParseBool(value)
 			if err != nil {
-				return fmt.Errorf("invalid bool value for %s: %s", name, value)
+				return newParseError(InvalidValue, name, value, "invalid bool value for %s: %s", name, value)
 			}
-			*f.Value = val
-			return nil
+			return c.setBoolValue(f, val)
 		case *StringSliceFlag:
 			if f.FlagOnly {
 				continue
@@ -1186,7 +1416,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			_, err := c.appendStringSliceValue(f, value)
 			return err
 		case *IntSliceFlag:
@@ -1206,7 +1436,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			_, err := c.appendIntSliceValue(f, value)
 			return err
 		case *Int64SliceFlag:
@@ -1226,7 +1456,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			_, err := c.appendInt64SliceValue(f, value)
 			return err
 		case *Float64SliceFlag:
@@ -1246,7 +1476,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			_, err := c.appendFloat64SliceValue(f, value)
 			return err
 		case *BoolSliceFlag:
@@ -1266,9 +1496,27 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 			if c.configured[name] {
 				continue // Already assigned
 			}
-			c.configured[name] = true
+			c.markConfigured(name)
 			_, err := c.appendBoolSliceValue(f, value)
 			return err
+		case valueFlag:
+			if f.getBase().FlagOnly {
+				continue
+			}
+			if f.isVariadic() {
+				handled, err := c.handleVariadicSliceFlag(name, value, positionalOnlyMode, func() error {
+					return c.setValue(f, value)
+				})
+				if handled {
+					return err
+				}
+				continue // Skip this variadic if not handled
+			}
+			if c.configured[name] {
+				continue // Already assigned
+			}
+			c.markConfigured(name)
+			return c.setValue(f, value)
 		}
 	}
 
@@ -1280,7 +1528,7 @@ func (c *Cmd) assignPositionalWithMode(value string, positionalOnlyMode bool) er
 		}
 	}
 
-	return fmt.Errorf("Too many positional arguments. Unused: [%s]", value)
+	return newParseError(UnexpectedArg, "", value, "Too many positional arguments. Unused: [%s]", value)
 }
 
 // handleVariadicSliceFlag handles the common logic for variadic slice flags
@@ -1293,11 +1541,12 @@ func (c *Cmd) handleVariadicSliceFlag(
 ) (bool, error) {
 	// Variadic positional - collect if this is the current one or no flag seen since last variadic
 	if c.lastVariadicFlag == name {
-		c.configured[name] = true
+		c.markConfigured(name)
 		return true, appendFunc()
 	}
 	// In positional-only mode (after --), continue appending to any configured variadic flag
 	if positionalOnlyMode && c.configured[name] {
+		c.markConfigured(name)
 		return true, appendFunc()
 	}
 	// If we saw a flag since last variadic, skip variadic flags that have already been used
@@ -1306,7 +1555,7 @@ func (c *Cmd) handleVariadicSliceFlag(
 	}
 	// Start new variadic only if we haven't seen a flag or this is a new variadic
 	if !c.sawFlag || c.lastVariadicFlag == "" {
-		c.configured[name] = true
+		c.markConfigured(name)
 		c.lastVariadicFlag = name
 		return true, appendFunc()
 	}
@@ -1324,18 +1573,19 @@ func (c *Cmd) setStringValue(f *StringFlag, value string) error {
 			}
 		}
 		if !valid {
-			return fmt.Errorf(
-				"Invalid '%s' value: %s (valid values: %s)",
+			return newParseError(ConstraintViolation, f.Name, value,
+				"Invalid '%s' value: %s (valid values: %s)%s",
 				f.Name,
 				value,
 				strings.Join(*f.EnumConstraint, ", "),
+				didYouMean(c.suggestFor(value, *f.EnumConstraint), ""),
 			)
 		}
 	}
 
 	if f.RegexConstraint != nil {
 		if !f.RegexConstraint.MatchString(value) {
-			return fmt.Errorf(
+			return newParseError(ConstraintViolation, f.Name, value,
 				"Invalid '%s' value: %s (must match regex: %s)",
 				f.Name,
 				value,
@@ -1344,20 +1594,25 @@ func (c *Cmd) setStringValue(f *StringFlag, value string) error {
 		}
 	}
 
+	if err := validateValue(f.Name, f.Validator, value, value); err != nil {
+		return err
+	}
+
 	*f.Value = value
 	return nil
 }
 
 func (c *Cmd) setIntValue(f *IntFlag, value string) error {
 	// Parse as int64 first to detect overflow
-	val64, err := strconv.ParseInt(value, 10, 64)
+	allowPrefixes := c.allowsIntPrefixes(f.allowPrefixes)
+	val64, err := parseIntLiteral(value, allowPrefixes, 64)
 	if err != nil {
-		return fmt.Errorf("invalid integer value for %s: %s", f.Name, value)
+		return newParseError(InvalidValue, f.Name, value, "invalid integer value for %s: %s%s", f.Name, value, invalidIntSuffix(allowPrefixes))
 	}
 
 	// Check for platform-specific int overflow
 	if val64 < int64(int(^uint(0)>>1)*-1-1) || val64 > int64(int(^uint(0)>>1)) {
-		return fmt.Errorf("integer overflow for %s: %s (value exceeds platform int range)", f.Name, value)
+		return newParseError(InvalidValue, f.Name, value, "integer overflow for %s: %s (value exceeds platform int range)", f.Name, value)
 	}
 
 	val := int(val64)
@@ -1366,9 +1621,9 @@ func (c *Cmd) setIntValue(f *IntFlag, value string) error {
 		inclusive := f.minInclusive == nil || *f.minInclusive // default to inclusive
 		if (inclusive && val < *f.min) || (!inclusive && val <= *f.min) {
 			if inclusive {
-				return fmt.Errorf("'%s' value %d is < minimum %d", f.Name, val, *f.min)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is < minimum %d", f.Name, val, *f.min)
 			} else {
-				return fmt.Errorf("'%s' value %d is <= minimum (exclusive) %d", f.Name, val, *f.min)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is <= minimum (exclusive) %d", f.Name, val, *f.min)
 			}
 		}
 	}
@@ -1377,30 +1632,35 @@ func (c *Cmd) setIntValue(f *IntFlag, value string) error {
 		inclusive := f.maxInclusive == nil || *f.maxInclusive // default to inclusive
 		if (inclusive && val > *f.max) || (!inclusive && val >= *f.max) {
 			if inclusive {
-				return fmt.Errorf("'%s' value %d is > maximum %d", f.Name, val, *f.max)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is > maximum %d", f.Name, val, *f.max)
 			} else {
-				return fmt.Errorf("'%s' value %d is >= maximum (exclusive) %d", f.Name, val, *f.max)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is >= maximum (exclusive) %d", f.Name, val, *f.max)
 			}
 		}
 	}
 
+	if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+		return err
+	}
+
 	*f.Value = val
 	return nil
 }
 
 func (c *Cmd) setInt64Value(f *Int64Flag, value string) error {
-	val, err := strconv.ParseInt(value, 10, 64)
+	allowPrefixes := c.allowsIntPrefixes(f.allowPrefixes)
+	val, err := parseIntLiteral(value, allowPrefixes, 64)
 	if err != nil {
-		return fmt.Errorf("invalid int64 value for %s: %s", f.Name, value)
+		return newParseError(InvalidValue, f.Name, value, "invalid int64 value for %s: %s%s", f.Name, value, invalidIntSuffix(allowPrefixes))
 	}
 
 	if f.min != nil {
 		inclusive := f.minInclusive == nil || *f.minInclusive // default to inclusive
 		if (inclusive && val < *f.min) || (!inclusive && val <= *f.min) {
 			if inclusive {
-				return fmt.Errorf("'%s' value %d is < minimum %d", f.Name, val, *f.min)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is < minimum %d", f.Name, val, *f.min)
 			} else {
-				return fmt.Errorf("'%s' value %d is <= minimum (exclusive) %d", f.Name, val, *f.min)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is <= minimum (exclusive) %d", f.Name, val, *f.min)
 			}
 		}
 	}
@@ -1409,13 +1669,17 @@ func (c *Cmd) setInt64Value(f *Int64Flag, value string) error {
 		inclusive := f.maxInclusive == nil || *f.maxInclusive // default to inclusive
 		if (inclusive && val > *f.max) || (!inclusive && val >= *f.max) {
 			if inclusive {
-				return fmt.Errorf("'%s' value %d is > maximum %d", f.Name, val, *f.max)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is > maximum %d", f.Name, val, *f.max)
 			} else {
-				return fmt.Errorf("'%s' value %d is >= maximum (exclusive) %d", f.Name, val, *f.max)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %d is >= maximum (exclusive) %d", f.Name, val, *f.max)
 			}
 		}
 	}
 
+	if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+		return err
+	}
+
 	*f.Value = val
 	return nil
 }
@@ -1423,16 +1687,16 @@ func (c *Cmd) setInt64Value(f *Int64Flag, value string) error {
 func (c *Cmd) setFloat64Value(f *Float64Flag, value string) error {
 	val, err := strconv.ParseFloat(value, 64)
 	if err != nil {
-		return fmt.Errorf("invalid float64 value for %s: %s", f.Name, value)
+		return newParseError(InvalidValue, f.Name, value, "invalid float64 value for %s: %s", f.Name, value)
 	}
 
 	if f.min != nil {
 		inclusive := f.minInclusive == nil || *f.minInclusive // default to inclusive
 		if (inclusive && val < *f.min) || (!inclusive && val <= *f.min) {
 			if inclusive {
-				return fmt.Errorf("'%s' value %g is < minimum %g", f.Name, val, *f.min)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %g is < minimum %g", f.Name, val, *f.min)
 			} else {
-				return fmt.Errorf("'%s' value %g is <= minimum (exclusive) %g", f.Name, val, *f.min)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %g is <= minimum (exclusive) %g", f.Name, val, *f.min)
 			}
 		}
 	}
@@ -1441,13 +1705,17 @@ func (c *Cmd) setFloat64Value(f *Float64Flag, value string) error {
 		inclusive := f.maxInclusive == nil || *f.maxInclusive // default to inclusive
 		if (inclusive && val > *f.max) || (!inclusive && val >= *f.max) {
 			if inclusive {
-				return fmt.Errorf("'%s' value %g is > maximum %g", f.Name, val, *f.max)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %g is > maximum %g", f.Name, val, *f.max)
 			} else {
-				return fmt.Errorf("'%s' value %g is >= maximum (exclusive) %g", f.Name, val, *f.max)
+				return newParseError(ConstraintViolation, f.Name, value, "'%s' value %g is >= maximum (exclusive) %g", f.Name, val, *f.max)
 			}
 		}
 	}
 
+	if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+		return err
+	}
+
 	*f.Value = val
 	return nil
 }
@@ -1467,6 +1735,59 @@ func (c *Cmd) parseBoolValue(value string) (bool, error) {
 	return val, nil
 }
 
+// setBoolValue assigns a parsed bool after running the flag's custom validator.
+func (c *Cmd) setBoolValue(f *BoolFlag, val bool) error {
+	if err := validateValue(f.Name, f.Validator, val, strconv.FormatBool(val)); err != nil {
+		return err
+	}
+	*f.Value = val
+	return nil
+}
+
+// setFlagFromString assigns a single string value to a flag through the same
+// conversion and constraint checks used for command-line input. Slice flags
+// append the value, splitting it on their separator if one is set.
+func (c *Cmd) setFlagFromString(flag any, value string) error {
+	switch f := flag.(type) {
+	case *BoolFlag:
+		val, err := c.parseBoolValue(value)
+		if err != nil {
+			return newParseError(InvalidValue, f.Name, value, "invalid bool value for %s: %s", f.Name, value)
+		}
+		return c.setBoolValue(f, val)
+	case *StringFlag:
+		return c.setStringValue(f, value)
+	case *IntFlag:
+		return c.setIntValue(f, value)
+	case *Int64Flag:
+		return c.setInt64Value(f, value)
+	case *Float64Flag:
+		return c.setFloat64Value(f, value)
+	case *StringSliceFlag:
+		_, err := c.appendStringSliceValue(f, value)
+		return err
+	case *IntSliceFlag:
+		_, err := c.appendIntSliceValue(f, value)
+		return err
+	case *Int64SliceFlag:
+		_, err := c.appendInt64SliceValue(f, value)
+		return err
+	case *Float64SliceFlag:
+		_, err := c.appendFloat64SliceValue(f, value)
+		return err
+	case *BoolSliceFlag:
+		_, err := c.appendBoolSliceValue(f, value)
+		return err
+	case valueFlag:
+		return c.setValue(f, value)
+	}
+
+	if base := getBaseFlag(flag); base != nil {
+		return NewProgrammingError(fmt.Sprintf("unsupported flag type for: %s", base.Name))
+	}
+	return NewProgrammingError("unsupported flag type")
+}
+
 func (c *Cmd) parseSliceFlag(args []string, index int, f *StringSliceFlag, cfg *parseCfg) (int, error) {
 	if !f.Variadic {
 		// Single value
@@ -1486,7 +1807,7 @@ func (c *Cmd) parseSliceFlag(args []string, index int, f *StringSliceFlag, cfg *
 			// actually parsing the candidate here (as this once did) executed
 			// every later flag in the argv once per probe, duplicating slice
 			// flag values.
-			if cfg.variadicUnknownFlags && !c.wouldParseAsFlag(arg) {
+			if cfg.variadicUnknownFlags && !c.wouldParseAsFlag(arg, cfg) {
 				if _, err := c.appendStringSliceValue(f, arg); err != nil {
 					return 0, err
 				}
@@ -1515,14 +1836,23 @@ func isNegativeNumberToken(arg string) bool {
 // wouldParseAsFlag reports whether parseFlag would resolve the "-"-prefixed
 // token against the registered flags, without performing the parse (and its
 // side effects). Mirrors parseFlag's resolution outside number-shorts mode.
-func (c *Cmd) wouldParseAsFlag(arg string) bool {
+func (c *Cmd) wouldParseAsFlag(arg string, cfg *parseCfg) bool {
 	if strings.HasPrefix(arg, "--") {
 		name := arg[2:]
 		if idx := strings.Index(name, "="); idx != -1 {
 			name = name[:idx]
 		}
-		_, ok := c.flags[name]
-		return ok
+		if _, ok := c.lookupNegatedFlag(name); ok {
+			return true
+		}
+		if _, ok := c.flags[c.resolveFlagAlias(name)]; ok {
+			return true
+		}
+		if cfg.abbreviations {
+			full, err := c.expandFlagAbbreviation(name)
+			return err == nil && full != ""
+		}
+		return false
 	}
 	shorts := arg[1:]
 	if idx := strings.Index(shorts, "="); idx != -1 {
@@ -1567,13 +1897,22 @@ func (c *Cmd) appendStringSliceValue(f *StringSliceFlag, value string) (int, err
 
 	if f.Separator != nil {
 		parts := strings.Split(value, *f.Separator)
+		// Check every element before storing any
+		vals := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if err := validateValue(f.Name, f.Validator, part, part); err != nil {
+				return 0, err
+			}
+			vals = append(vals, part)
+		}
 		if shouldReplace {
 			*f.Value = make([]string, 0, len(parts))
 		}
-		for _, part := range parts {
-			*f.Value = append(*f.Value, part)
-		}
+		*f.Value = append(*f.Value, vals...)
 	} else {
+		if err := validateValue(f.Name, f.Validator, value, value); err != nil {
+			return 0, err
+		}
 		if shouldReplace {
 			*f.Value = make([]string, 0, 1)
 		}
@@ -1628,27 +1967,37 @@ func (c *Cmd) appendIntSliceValue(f *IntSliceFlag, value string) (int, error) {
 		}
 	}
 
+	allowPrefixes := c.allowsIntPrefixes(f.allowPrefixes)
 	if f.Separator != nil {
 		parts := strings.Split(value, *f.Separator)
-		if shouldReplace {
-			*f.Value = make([]int, 0, len(parts))
-		}
+		// Check every element before storing any
+		vals := make([]int, 0, len(parts))
 		for _, part := range parts {
-			val, err := strconv.Atoi(part)
+			val, err := parseIntLiteral(part, allowPrefixes, strconv.IntSize)
 			if err != nil {
-				return 0, fmt.Errorf("invalid integer value for %s: %s", f.Name, part)
+				return 0, newParseError(InvalidValue, f.Name, part, "invalid integer value for %s: %s%s", f.Name, part, invalidIntSuffix(allowPrefixes))
+			}
+			if err := validateValue(f.Name, f.Validator, int(val), part); err != nil {
+				return 0, err
 			}
-			*f.Value = append(*f.Value, val)
+			vals = append(vals, int(val))
 		}
-	} else {
 		if shouldReplace {
-			*f.Value = make([]int, 0, 1)
+			*f.Value = make([]int, 0, len(parts))
 		}
-		val, err := strconv.Atoi(value)
+		*f.Value = append(*f.Value, vals...)
+	} else {
+		val, err := parseIntLiteral(value, allowPrefixes, strconv.IntSize)
 		if err != nil {
-			return 0, fmt.Errorf("invalid integer value for %s: %s", f.Name, value)
+			return 0, newParseError(InvalidValue, f.Name, value, "invalid integer value for %s: %s%s", f.Name, value, invalidIntSuffix(allowPrefixes))
 		}
-		*f.Value = append(*f.Value, val)
+		if err := validateValue(f.Name, f.Validator, int(val), value); err != nil {
+			return 0, err
+		}
+		if shouldReplace {
+			*f.Value = make([]int, 0, 1)
+		}
+		*f.Value = append(*f.Value, int(val))
 	}
 	return 2, nil
 }
@@ -1698,25 +2047,35 @@ func (c *Cmd) appendInt64SliceValue(f *Int64SliceFlag, value string) (int, error
 		}
 	}
 
+	allowPrefixes := c.allowsIntPrefixes(f.allowPrefixes)
 	if f.Separator != nil {
 		parts := strings.Split(value, *f.Separator)
-		if shouldReplace {
-			*f.Value = make([]int64, 0, len(parts))
-		}
+		// Check every element before storing any
+		vals := make([]int64, 0, len(parts))
 		for _, part := range parts {
-			val, err := strconv.ParseInt(part, 10, 64)
+			val, err := parseIntLiteral(part, allowPrefixes, 64)
 			if err != nil {
-				return 0, fmt.Errorf("invalid int64 value for %s: %s", f.Name, part)
+				return 0, newParseError(InvalidValue, f.Name, part, "invalid int64 value for %s: %s%s", f.Name, part, invalidIntSuffix(allowPrefixes))
 			}
-			*f.Value = append(*f.Value, val)
+			if err := validateValue(f.Name, f.Validator, val, part); err != nil {
+				return 0, err
+			}
+			vals = append(vals, val)
 		}
-	} else {
 		if shouldReplace {
-			*f.Value = make([]int64, 0, 1)
+			*f.Value = make([]int64, 0, len(parts))
 		}
-		val, err := strconv.ParseInt(value, 10, 64)
+		*f.Value = append(*f.Value, vals...)
+	} else {
+		val, err := parseIntLiteral(value, allowPrefixes, 64)
 		if err != nil {
-			return 0, fmt.Errorf("invalid int64 value for %s: %s", f.Name, value)
+			return 0, newParseError(InvalidValue, f.Name, value, "invalid int64 value for %s: %s%s", f.Name, value, invalidIntSuffix(allowPrefixes))
+		}
+		if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+			return 0, err
+		}
+		if shouldReplace {
+			*f.Value = make([]int64, 0, 1)
 		}
 		*f.Value = append(*f.Value, val)
 	}
@@ -1770,23 +2129,32 @@ func (c *Cmd) appendFloat64SliceValue(f *Float64SliceFlag, value string) (int, e
 
 	if f.Separator != nil {
 		parts := strings.Split(value, *f.Separator)
-		if shouldReplace {
-			*f.Value = make([]float64, 0, len(parts))
-		}
+		// Check every element before storing any
+		vals := make([]float64, 0, len(parts))
 		for _, part := range parts {
 			val, err := strconv.ParseFloat(part, 64)
 			if err != nil {
-				return 0, fmt.Errorf("invalid float64 value for %s: %s", f.Name, part)
+				return 0, newParseError(InvalidValue, f.Name, part, "invalid float64 value for %s: %s", f.Name, part)
+			}
+			if err := validateValue(f.Name, f.Validator, val, part); err != nil {
+				return 0, err
 			}
-			*f.Value = append(*f.Value, val)
+			vals = append(vals, val)
 		}
-	} else {
 		if shouldReplace {
-			*f.Value = make([]float64, 0, 1)
+			*f.Value = make([]float64, 0, len(parts))
 		}
+		*f.Value = append(*f.Value, vals...)
+	} else {
 		val, err := strconv.ParseFloat(value, 64)
 		if err != nil {
-			return 0, fmt.Errorf("invalid float64 value for %s: %s", f.Name, value)
+			return 0, newParseError(InvalidValue, f.Name, value, "invalid float64 value for %s: %s", f.Name, value)
+		}
+		if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+			return 0, err
+		}
+		if shouldReplace {
+			*f.Value = make([]float64, 0, 1)
 		}
 		*f.Value = append(*f.Value, val)
 	}
@@ -1840,9 +2208,8 @@ func (c *Cmd) appendBoolSliceValue(f *BoolSliceFlag, value string) (int, error)
 
 	if f.Separator != nil {
 		parts := strings.Split(value, *f.Separator)
-		if shouldReplace {
-			*f.Value = make([]bool, 0, len(parts))
-		}
+		// Check every element before storing any
+		vals := make([]bool, 0, len(parts))
 		for _, part := range parts {
 			val, err := strconv.ParseBool(part)
 			if err != nil {
@@ -1852,15 +2219,19 @@ func (c *Cmd) appendBoolSliceValue(f *BoolSliceFlag, value string) (int, error)
 				} else if part == "1" {
 					val = true
 				} else {
-					return 0, fmt.Errorf("invalid bool value for %s: %s", f.Name, part)
+					return 0, newParseError(InvalidValue, f.Name, part, "invalid bool value for %s: %s", f.Name, part)
 				}
 			}
-			*f.Value = append(*f.Value, val)
+			if err := validateValue(f.Name, f.Validator, val, part); err != nil {
+				return 0, err
+			}
+			vals = append(vals, val)
 		}
-	} else {
 		if shouldReplace {
-			*f.Value = make([]bool, 0, 1)
+			*f.Value = make([]bool, 0, len(parts))
 		}
+		*f.Value = append(*f.Value, vals...)
+	} else {
 		val, err := strconv.ParseBool(value)
 		if err != nil {
 			// Try parsing as 0/1
@@ -1869,9 +2240,15 @@ func (c *Cmd) appendBoolSliceValue(f *BoolSliceFlag, value string) (int, error)
 			} else if value == "1" {
 				val = true
 			} else {
-				return 0, fmt.Errorf("invalid bool value for %s: %s", f.Name, value)
+				return 0, newParseError(InvalidValue, f.Name, value, "invalid bool value for %s: %s", f.Name, value)
 			}
 		}
+		if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+			return 0, err
+		}
+		if shouldReplace {
+			*f.Value = make([]bool, 0, 1)
+		}
 		*f.Value = append(*f.Value, val)
 	}
 	return 2, nil
@@ -1909,8 +2286,13 @@ func (c *Cmd) validateBeforeParsing() error {
 func (c *Cmd) validateConstraintReferences() error {
 	// Get all valid flag names
 	validFlags := make(map[string]bool)
-	for name := range c.flags {
+	for name, flag := range c.flags {
 		validFlags[name] = true
+		if base := getBaseFlag(flag); base != nil {
+			for _, alias := range base.Aliases {
+				validFlags[alias] = true
+			}
+		}
 	}
 
 	// Check all requires/excludes constraints
@@ -1949,6 +2331,9 @@ func (c *Cmd) validateConstraintReferences() error {
 		case *BoolSliceFlag:
 			requires = f.Requires
 			excludes = f.Excludes
+		case valueFlag:
+			requires = f.getBase().Requires
+			excludes = f.getBase().Excludes
 		}
 
 		// Validate requires constraints
@@ -1968,12 +2353,21 @@ func (c *Cmd) validateConstraintReferences() error {
 				}
 			}
 		}
+
+		// Validate conditional constraints
+		if base := getBaseFlag(flag); base != nil {
+			if err := c.validateConditionReferences(base, validFlags); err != nil {
+				return err
+			}
+		}
 	}
 
-	return nil
+	return c.validateFlagGroupReferences(validFlags)
 }
 
-func (c *Cmd) validateRequired() error {
+// validateRequired checks relational constraints, flag groups and required
+// flags, returning the first violation unless cfg is collecting errors.
+func (c *Cmd) validateRequired(cfg *parseCfg) error {
 	// A configured bypass-validation flag (e.g. --version style flags) skips
 	// validation entirely - including the relational constraint pass below,
 	// which previously still ran because this check sat between the passes.
@@ -2014,23 +2408,38 @@ func (c *Cmd) validateRequired() error {
 				requires = f.Requires
 			case *SliceFlag[bool]:
 				requires = f.Requires
+			case valueFlag:
+				requires = f.getBase().Requires
 			}
 
 			if requires != nil {
 				for _, req := range *requires {
 					if !c.flagConfiguredForRelationalConstraints(req) {
-						return fmt.Errorf("Invalid args: '%s' requires '%s', but '%s' was not set", name, req, req)
+						err := newParseError(RequiresMissing, name, "", "Invalid args: '%s' requires '%s', but '%s' was not set", name, req, req)
+						if err := cfg.report(err); err != nil {
+							return err
+						}
 					}
 				}
 			}
 		}
 
 		// Check exclusion constraints using the new helper
-		if err := c.checkExclusion(name); err != nil {
+		if err := cfg.report(c.checkExclusion(name)); err != nil {
+			return err
+		}
+
+		// And constraints that only apply under a condition
+		if err := cfg.report(c.checkConditionalConstraints(name)); err != nil {
 			return err
 		}
 	}
 
+	// Then flag groups, which constrain several flags at once
+	if err := c.checkFlagGroups(cfg); err != nil {
+		return err
+	}
+
 	// Second pass: Check if required flags are missing
 	// This runs after relational constraints so more specific errors take precedence
 	// Check in registration order: positional flags first, then non-positional flags
@@ -2038,20 +2447,20 @@ func (c *Cmd) validateRequired() error {
 
 	// Check positional flags first
 	for _, name := range c.positional {
-		if c.isFlagRequired(name) && !c.configured[name] && !c.isFlagExcludedByConfiguredFlag(name) {
+		if c.isFlagRequired(name) && !c.configured[name] && !c.isFlagExcludedByConfiguredFlag(name) && !c.isFlagInGroup(name) {
 			missingRequired = append(missingRequired, name)
 		}
 	}
 
 	// Then check non-positional flags
 	for _, name := range c.nonPositional {
-		if c.isFlagRequired(name) && !c.configured[name] && !c.isFlagExcludedByConfiguredFlag(name) {
+		if c.isFlagRequired(name) && !c.configured[name] && !c.isFlagExcludedByConfiguredFlag(name) && !c.isFlagInGroup(name) {
 			missingRequired = append(missingRequired, name)
 		}
 	}
 
 	if len(missingRequired) > 0 {
-		return fmt.Errorf("Missing required arguments: [%s]", strings.Join(missingRequired, ", "))
+		return cfg.report(newParseError(MissingRequired, missingRequired[0], "", "Missing required arguments: [%s]", strings.Join(missingRequired, ", ")))
 	}
 	return nil
 }
@@ -2060,6 +2469,10 @@ func (c *Cmd) isFlagRequired(name string) bool {
 	if !exists {
 		return false
 	}
+	// Flags with RequiredIf conditions are checked by checkConditionalConstraints
+	if base := getBaseFlag(flag); base != nil && len(base.RequiredIf) > 0 {
+		return false
+	}
 
 	switch f := flag.(type) {
 	case *StringFlag:
@@ -2088,6 +2501,9 @@ func (c *Cmd) isFlagRequired(name string) bool {
 	case *SliceFlag[bool]:
 		// Boolean slice flags have implicit default of empty slice, so never required
 		return false
+	case valueFlag:
+		// Variadic slice flags have implicit default of empty slice, so never required
+		return !f.isVariadic() && !f.getBase().Optional && !f.hasDefault()
 	}
 	return false
 }
@@ -2122,12 +2538,14 @@ func (c *Cmd) checkExclusion(flagName string) error {
 			excludes = f.Excludes
 		case *SliceFlag[bool]:
 			excludes = f.Excludes
+		case valueFlag:
+			excludes = f.getBase().Excludes
 		}
 
 		if excludes != nil {
 			for _, excluded := range *excludes {
 				if c.flagExplicitlySetForExclusion(excluded) {
-					return fmt.Errorf(
+					return newParseError(Excluded, flagName, "",
 						"Invalid args: '%s' excludes '%s', but '%s' was set",
 						flagName,
 						excluded,
@@ -2171,12 +2589,14 @@ func (c *Cmd) checkExclusion(flagName string) error {
 			otherExcludes = f.Excludes
 		case *SliceFlag[bool]:
 			otherExcludes = f.Excludes
+		case valueFlag:
+			otherExcludes = f.getBase().Excludes
 		}
 
 		if otherExcludes != nil {
 			for _, excluded := range *otherExcludes {
-				if excluded == flagName {
-					return fmt.Errorf(
+				if c.resolveFlagAlias(excluded) == flagName {
+					return newParseError(Excluded, otherName, "",
 						"Invalid args: '%s' excludes '%s', but '%s' was set",
 						otherName,
 						flagName,
@@ -2239,6 +2659,10 @@ func (c *Cmd) hasConfiguredBypassFlag() bool {
 			if f.BypassValidation {
 				return true
 			}
+		case valueFlag:
+			if f.getBase().BypassValidation {
+				return true
+			}
 		}
 	}
 	return false
@@ -2250,6 +2674,7 @@ func (c *Cmd) hasConfiguredBypassFlag() bool {
 // a default expresses the author's fallback, not user intent, so it cannot
 // conflict with anything the user actually passed.
 func (c *Cmd) flagExplicitlySetForExclusion(name string) bool {
+	name = c.resolveFlagAlias(name)
 	flag, exists := c.flags[name]
 	if !exists || !c.configured[name] {
 		return false
@@ -2267,6 +2692,7 @@ func (c *Cmd) flagExplicitlySetForExclusion(name string) bool {
 // deliberately, so a defaulted flag both triggers its own requires constraints and satisfies
 // others' (see Test_RequiresWithDefaults_Scenario).
 func (c *Cmd) flagConfiguredForRelationalConstraints(name string) bool {
+	name = c.resolveFlagAlias(name)
 	if flag, exists := c.flags[name]; exists {
 		switch f := flag.(type) {
 		case *BoolFlag:
@@ -2275,6 +2701,9 @@ func (c *Cmd) flagConfiguredForRelationalConstraints(name string) bool {
 				return true
 			}
 			return false
+		case *CountFlag:
+			// Likewise, count flags only when counted at least once
+			return f.Value != nil && *f.Value > 0
 		default:
 			// For all other flag types, use the normal flagHasValue logic
 			return c.flagHasValue(name)
@@ -2313,6 +2742,8 @@ func (c *Cmd) flagHasValue(name string) bool {
 			return f.Default != nil
 		case *SliceFlag[bool]:
 			return f.Default != nil
+		case valueFlag:
+			return f.hasDefault()
 		}
 	}
 
@@ -2354,11 +2785,13 @@ func (c *Cmd) isFlagExcludedByConfiguredFlag(flagName string) bool {
 				excludes = f.Excludes
 			case *SliceFlag[bool]:
 				excludes = f.Excludes
+			case valueFlag:
+				excludes = f.getBase().Excludes
 			}
 
 			if excludes != nil {
 				for _, excluded := range *excludes {
-					if excluded == flagName {
+					if c.resolveFlagAlias(excluded) == flagName {
 						return true
 					}
 				}
@@ -1,6 +1,7 @@
 package ra
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +13,10 @@ import (
 // It receives the prefix the user has typed so far and returns candidates plus a directive.
 type CompletionFunc func(toComplete string) ([]string, CompletionDirective)
 
+// ContextCompletionFunc is a CompletionFunc that also receives the context passed to
+// ExecuteContext, or context.Background() if completion wasn't reached through it.
+type ContextCompletionFunc func(ctx context.Context, toComplete string) ([]string, CompletionDirective)
+
 // CompletionDirective is a bitmask that tells the shell how to interpret completion results.
 type CompletionDirective int
 
@@ -48,7 +53,7 @@ func (c *Cmd) EnableCompletion() *Cmd {
 
 // handleCompletion processes a __complete invocation and writes candidates to stdout.
 func (c *Cmd) handleCompletion(args []string) error {
-	candidates, directive := c.computeCompletions(args)
+	candidates, directive := c.computeCompletions(c.Context(), args)
 
 	var sb strings.Builder
 	for _, candidate := range candidates {
@@ -62,7 +67,7 @@ func (c *Cmd) handleCompletion(args []string) error {
 }
 
 // computeCompletions determines completion candidates for the given args.
-func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective) {
+func (c *Cmd) computeCompletions(ctx context.Context, args []string) ([]string, CompletionDirective) {
 	// Walk subcommand tree, skipping flags (and their values) to find
 	// the active subcommand. This mirrors how the parser handles
 	// interleaved flags and subcommands (e.g., "mycmd --verbose add").
@@ -91,8 +96,9 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 			continue
 		}
 
-		if subCmd, exists := activeCmd.subCmds[arg]; exists {
+		if subCmd, exists := activeCmd.lookupSubCmd(arg); exists {
 			activeCmd.applyGlobalFlags(subCmd)
+			subCmd.parentNegatable = activeCmd.negatableDefault()
 			activeCmd = subCmd
 			consumed = i + 1
 			i++
@@ -111,6 +117,7 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 		if _, exists := activeCmd.flags["help"]; !exists {
 			NewBool("help").SetShort("h").
 				SetUsage("Print usage string.").
+				SetNegatable(false).
 				SetOptional(true).
 				Register(activeCmd, WithGlobal(true))
 		}
@@ -142,17 +149,21 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 		if strings.HasPrefix(arg, "--") {
 			flagPart := arg[2:]
 			if idx := strings.Index(flagPart, "="); idx != -1 {
-				usedFlags[flagPart[:idx]] = true
+				usedFlags[activeCmd.resolveFlagAlias(flagPart[:idx])] = true
 				continue
 			}
 
+			flagPart = activeCmd.resolveFlagAlias(flagPart)
 			flag, exists := activeCmd.flags[flagPart]
 			if !exists {
+				if f, ok := activeCmd.lookupNegatedFlag(flagPart); ok {
+					usedFlags[f.Name] = true
+				}
 				continue
 			}
 			usedFlags[flagPart] = true
 
-			if !isBoolFlag(flag) {
+			if takesNextArg(flag) {
 				if i+1 < len(precedingArgs) {
 					i++ // skip the value
 				} else {
@@ -180,7 +191,7 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 				flag := activeCmd.flags[flagName]
 				usedFlags[flagName] = true
 
-				if !isBoolFlag(flag) && j == len(shorts)-1 {
+				if takesNextArg(flag) && j == len(shorts)-1 {
 					if i+1 < len(precedingArgs) {
 						i++
 					} else {
@@ -198,12 +209,12 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 
 	// After --, everything is positional (no flags, no subcommands)
 	if sawDashDash {
-		return activeCmd.completeSubcommandsAndPositionals(toComplete, positionalCount, false)
+		return activeCmd.completeSubcommandsAndPositionals(ctx, toComplete, positionalCount, false)
 	}
 
 	// Case 1: Previous arg was a value-taking flag waiting for its value
 	if prevNeedsValue {
-		return activeCmd.completeFlagValue(prevFlagName, toComplete)
+		return activeCmd.completeFlagValue(ctx, prevFlagName, toComplete)
 	}
 
 	// Case 2: --flag=prefix syntax
@@ -212,7 +223,7 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 		flagName := toComplete[2:eqIdx]
 		valuePrefix := toComplete[eqIdx+1:]
 
-		candidates, directive := activeCmd.completeFlagValue(flagName, valuePrefix)
+		candidates, directive := activeCmd.completeFlagValue(ctx, flagName, valuePrefix)
 		prefix := toComplete[:eqIdx+1]
 		for i, c := range candidates {
 			candidates[i] = prefix + c
@@ -230,7 +241,7 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 		if len(shortPart) > 0 {
 			lastChar := string(shortPart[len(shortPart)-1])
 			if flagName, exists := activeCmd.shortToName[lastChar]; exists {
-				candidates, directive := activeCmd.completeFlagValue(flagName, valuePrefix)
+				candidates, directive := activeCmd.completeFlagValue(ctx, flagName, valuePrefix)
 				prefix := toComplete[:eqIdx+1]
 				for i, c := range candidates {
 					candidates[i] = prefix + c
@@ -253,7 +264,7 @@ func (c *Cmd) computeCompletions(args []string) ([]string, CompletionDirective)
 	}
 
 	// Case 6: Empty or non-dash - offer subcommands + positional completions
-	return activeCmd.completeSubcommandsAndPositionals(toComplete, positionalCount, true)
+	return activeCmd.completeSubcommandsAndPositionals(ctx, toComplete, positionalCount, true)
 }
 
 // scanFlags marks a single flag arg as used in the usedFlags map.
@@ -261,11 +272,13 @@ func scanFlags(cmd *Cmd, arg string, usedFlags map[string]bool) {
 	if strings.HasPrefix(arg, "--") {
 		flagPart := arg[2:]
 		if idx := strings.Index(flagPart, "="); idx != -1 {
-			usedFlags[flagPart[:idx]] = true
+			usedFlags[cmd.resolveFlagAlias(flagPart[:idx])] = true
 			return
 		}
-		if _, exists := cmd.flags[flagPart]; exists {
-			usedFlags[flagPart] = true
+		if name := cmd.resolveFlagAlias(flagPart); cmd.flags[name] != nil {
+			usedFlags[name] = true
+		} else if f, ok := cmd.lookupNegatedFlag(flagPart); ok {
+			usedFlags[f.Name] = true
 		}
 	} else if strings.HasPrefix(arg, "-") && arg != "-" {
 		shorts := arg[1:]
@@ -285,9 +298,22 @@ func scanFlags(cmd *Cmd, arg string, usedFlags map[string]bool) {
 	}
 }
 
+// complete runs the flag's completion function, if it has one.
+func (f *BaseFlag) complete(ctx context.Context, toComplete string) ([]string, CompletionDirective, bool) {
+	if f.ContextCompletionFunc != nil {
+		vals, dir := f.ContextCompletionFunc(ctx, toComplete)
+		return vals, dir, true
+	}
+	if f.CompletionFunc != nil {
+		vals, dir := f.CompletionFunc(toComplete)
+		return vals, dir, true
+	}
+	return nil, CompletionDirectiveDefault, false
+}
+
 // completeFlagValue completes the value for a specific flag.
-func (c *Cmd) completeFlagValue(flagName string, toComplete string) ([]string, CompletionDirective) {
-	flag, exists := c.flags[flagName]
+func (c *Cmd) completeFlagValue(ctx context.Context, flagName string, toComplete string) ([]string, CompletionDirective) {
+	flag, exists := c.flags[c.resolveFlagAlias(flagName)]
 	if !exists {
 		return nil, CompletionDirectiveDefault
 	}
@@ -298,8 +324,8 @@ func (c *Cmd) completeFlagValue(flagName string, toComplete string) ([]string, C
 	}
 
 	// Priority 1: CompletionFunc
-	if base.CompletionFunc != nil {
-		return base.CompletionFunc(toComplete)
+	if vals, dir, ok := base.complete(ctx, toComplete); ok {
+		return vals, dir
 	}
 
 	// Priority 2: EnumConstraint (StringFlag only)
@@ -327,8 +353,8 @@ func (c *Cmd) completeFlagNames(prefix string, usedFlags map[string]bool) ([]str
 			continue
 		}
 
-		// Skip hidden flags
-		if base.Hidden {
+		// Skip hidden and deprecated flags
+		if base.Hidden || base.Deprecated != "" {
 			continue
 		}
 
@@ -338,13 +364,22 @@ func (c *Cmd) completeFlagNames(prefix string, usedFlags map[string]bool) ([]str
 		}
 
 		// Skip already-used non-slice flags
-		if usedFlags[name] && !isSliceFlag(flag) {
+		if usedFlags[name] && !isRepeatableFlag(flag) {
 			continue
 		}
 
 		if strings.HasPrefix(name, prefix) {
 			candidates = append(candidates, "--"+name)
 		}
+		// Aliases complete once typed into, rather than cluttering the full list
+		for _, alias := range base.visibleAliases() {
+			if prefix != "" && strings.HasPrefix(alias, prefix) {
+				candidates = append(candidates, "--"+alias)
+			}
+		}
+		if f, ok := flag.(*BoolFlag); ok && c.isNegatable(f) && strings.HasPrefix(negationPrefix+name, prefix) {
+			candidates = append(candidates, "--"+negationPrefix+name)
+		}
 	}
 
 	sort.Strings(candidates)
@@ -361,7 +396,7 @@ func (c *Cmd) completeShortAndLongFlags(toComplete string, usedFlags map[string]
 			continue
 		}
 
-		if base.Hidden {
+		if base.Hidden || base.Deprecated != "" {
 			continue
 		}
 
@@ -369,7 +404,7 @@ func (c *Cmd) completeShortAndLongFlags(toComplete string, usedFlags map[string]
 			continue
 		}
 
-		if usedFlags[name] && !isSliceFlag(flag) {
+		if usedFlags[name] && !isRepeatableFlag(flag) {
 			continue
 		}
 
@@ -377,6 +412,9 @@ func (c *Cmd) completeShortAndLongFlags(toComplete string, usedFlags map[string]
 		if strings.HasPrefix("--"+name, toComplete) {
 			candidates = append(candidates, "--"+name)
 		}
+		if f, ok := flag.(*BoolFlag); ok && c.isNegatable(f) && strings.HasPrefix("--"+negationPrefix+name, toComplete) {
+			candidates = append(candidates, "--"+negationPrefix+name)
+		}
 
 		// Add short form
 		if base.Short != "" {
@@ -395,6 +433,7 @@ func (c *Cmd) completeShortAndLongFlags(toComplete string, usedFlags map[string]
 // positionalCount is how many positional args have already been consumed.
 // includeSubcmds controls whether subcommand names are offered (false after --).
 func (c *Cmd) completeSubcommandsAndPositionals(
+	ctx context.Context,
 	toComplete string,
 	positionalCount int,
 	includeSubcmds bool,
@@ -407,12 +446,18 @@ func (c *Cmd) completeSubcommandsAndPositionals(
 	// flags behave in completion (HiddenInShortHelp never affects completion).
 	if includeSubcmds {
 		for name, subCmd := range c.subCmds {
-			if subCmd.hidden {
+			if subCmd.hidden || subCmd.deprecated != "" {
 				continue
 			}
 			if strings.HasPrefix(name, toComplete) {
 				candidates = append(candidates, name)
 			}
+			// Aliases complete once typed into, rather than cluttering the full list
+			for _, alias := range subCmd.visibleAliases() {
+				if toComplete != "" && strings.HasPrefix(alias, toComplete) {
+					candidates = append(candidates, alias)
+				}
+			}
 		}
 	}
 	subCmdCount := len(candidates)
@@ -434,8 +479,7 @@ func (c *Cmd) completeSubcommandsAndPositionals(
 		}
 
 		// This is the positional we're completing
-		if base.CompletionFunc != nil {
-			vals, dir := base.CompletionFunc(toComplete)
+		if vals, dir, ok := base.complete(ctx, toComplete); ok {
 			candidates = append(candidates, vals...)
 			// Only use the CompletionFunc's directive when there are no
 			// subcommand candidates, to avoid downgrading from NoFileComp
@@ -526,13 +570,28 @@ func (c *Cmd) GenZshCompletion(w io.Writer) error {
 }
 
 func isSliceFlag(flag any) bool {
-	switch flag.(type) {
+	switch f := flag.(type) {
 	case *StringSliceFlag, *IntSliceFlag, *Int64SliceFlag, *Float64SliceFlag, *BoolSliceFlag:
 		return true
+	case valueFlag:
+		return f.isSlice()
 	}
 	return false
 }
 
+// takesNextArg returns true for flags given as "--flag value", whose value is
+// the next argument.
+func takesNextArg(flag any) bool {
+	_, optionalValue := getNoOptDefault(flag)
+	return !takesNoValue(flag) && !optionalValue
+}
+
+// isRepeatableFlag returns true for flags that are still offered after use.
+func isRepeatableFlag(flag any) bool {
+	_, isCount := flag.(*CountFlag)
+	return isCount || isSliceFlag(flag)
+}
+
 func isVariadicFlag(flag any) bool {
 	switch f := flag.(type) {
 	case *StringSliceFlag:
@@ -545,6 +604,8 @@ func isVariadicFlag(flag any) bool {
 		return f.Variadic
 	case *BoolSliceFlag:
 		return f.Variadic
+	case valueFlag:
+		return f.isVariadic()
 	}
 	return false
 }
@@ -558,11 +619,11 @@ func flagConsumedArgs(cmd *Cmd, arg string) int {
 		if strings.Contains(flagPart, "=") {
 			return 1
 		}
-		flag, exists := cmd.flags[flagPart]
+		flag, exists := cmd.flags[cmd.resolveFlagAlias(flagPart)]
 		if !exists {
 			return 1
 		}
-		if isBoolFlag(flag) {
+		if !takesNextArg(flag) {
 			return 1
 		}
 		return 2
@@ -585,7 +646,7 @@ func flagConsumedArgs(cmd *Cmd, arg string) int {
 		if !exists {
 			return 1
 		}
-		if isBoolFlag(flag) {
+		if !takesNextArg(flag) {
 			return 1
 		}
 		return 2
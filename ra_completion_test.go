@@ -2,6 +2,7 @@ package ra
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -291,6 +292,91 @@ func TestCompletionBoolFlagNoValue(t *testing.T) {
 	assert.NotContains(t, candidates, "--verbose") // already used
 }
 
+func TestCompletionCountFlagNoValue(t *testing.T) {
+	cmd := NewCmd("test").EnableCompletion()
+	NewCount("verbose").SetShort("v").Register(cmd)
+	NewString("output").SetOptional(true).SetFlagOnly(true).Register(cmd)
+
+	// After a count flag, should offer more flags, including the count again
+	output, _ := parseCompletion(cmd, []string{"__complete", "-vv", "--"})
+	candidates, _ := parseCompletionLines(output)
+
+	assert.Contains(t, candidates, "--output")
+	assert.Contains(t, candidates, "--verbose")
+}
+
+func TestCompletionNegatableBoolBothForms(t *testing.T) {
+	cmd := NewCmd("test").EnableCompletion()
+	NewBool("color").SetNegatable(true).Register(cmd)
+	NewBool("cache").Register(cmd)
+
+	output, _ := parseCompletion(cmd, []string{"__complete", "--"})
+	candidates, _ := parseCompletionLines(output)
+	assert.Contains(t, candidates, "--color")
+	assert.Contains(t, candidates, "--no-color")
+	assert.Contains(t, candidates, "--cache")
+	assert.NotContains(t, candidates, "--no-cache")
+
+	// Either form uses up the flag
+	output, _ = parseCompletion(cmd, []string{"__complete", "--no-color", "--"})
+	candidates, _ = parseCompletionLines(output)
+	assert.NotContains(t, candidates, "--color")
+	assert.NotContains(t, candidates, "--no-color")
+}
+
+func TestCompletionNoOptDefaultNoValue(t *testing.T) {
+	cmd := NewCmd("test").EnableCompletion()
+	NewString("color").
+		SetNoOptDefault("always").
+		SetEnumConstraint([]string{"always", "never"}).
+		SetOptional(true).
+		SetFlagOnly(true).
+		Register(cmd)
+	NewString("output").SetOptional(true).SetFlagOnly(true).Register(cmd)
+
+	// The value is optional, so the next word isn't completed as one
+	output, _ := parseCompletion(cmd, []string{"__complete", "--color", "--"})
+	candidates, _ := parseCompletionLines(output)
+	assert.Contains(t, candidates, "--output")
+	assert.NotContains(t, candidates, "always")
+
+	output, _ = parseCompletion(cmd, []string{"__complete", "--color="})
+	candidates, _ = parseCompletionLines(output)
+	assert.Equal(t, []string{"--color=always", "--color=never"}, candidates)
+}
+
+func TestCompletionAliases(t *testing.T) {
+	cmd := NewCmd("test").EnableCompletion()
+	NewString("path").SetAliases("dir", "directory").SetOptional(true).SetFlagOnly(true).Register(cmd)
+	NewBool("verbose").Register(cmd)
+	cmd.RegisterCmd(NewCmd("remove").SetAliases("rm"))
+
+	// Aliases aren't listed until typed into
+	output, _ := parseCompletion(cmd, []string{"__complete", "--"})
+	candidates, _ := parseCompletionLines(output)
+	assert.Contains(t, candidates, "--path")
+	assert.NotContains(t, candidates, "--dir")
+
+	output, _ = parseCompletion(cmd, []string{"__complete", "--di"})
+	candidates, _ = parseCompletionLines(output)
+	assert.Equal(t, []string{"--dir", "--directory"}, candidates)
+
+	// An alias uses up the flag and takes its value
+	output, _ = parseCompletion(cmd, []string{"__complete", "--dir", "a", "--"})
+	candidates, _ = parseCompletionLines(output)
+	assert.NotContains(t, candidates, "--path")
+	assert.Contains(t, candidates, "--verbose")
+
+	output, _ = parseCompletion(cmd, []string{"__complete", "r"})
+	candidates, _ = parseCompletionLines(output)
+	assert.Equal(t, []string{"remove", "rm"}, candidates)
+
+	// Subcommand aliases are descended into
+	output, _ = parseCompletion(cmd, []string{"__complete", "rm", "--"})
+	candidates, _ = parseCompletionLines(output)
+	assert.NotContains(t, candidates, "--path")
+}
+
 func TestCompletionEqualsValueSyntax(t *testing.T) {
 	cmd := NewCmd("test").EnableCompletion()
 	NewString("format").
@@ -1027,3 +1113,72 @@ func TestBashCompletionFiltersPrefix(t *testing.T) {
 	assert.Contains(t, outStr, "CANDIDATE:stop")
 	assert.NotContains(t, outStr, "CANDIDATE:restart")
 }
+
+func TestCompletionCustomFlags(t *testing.T) {
+	cmd := NewCmd("test").EnableCompletion()
+	NewCustom("level", parseLogLevel, formatLogLevel).
+		SetFlagOnly(true).
+		SetCompletionFunc(func(toComplete string) ([]string, CompletionDirective) {
+			return []string{"debug", "info"}, CompletionDirectiveNoFileComp
+		}).
+		Register(cmd)
+	NewCustomSlice("levels", parseLogLevel, formatLogLevel).SetFlagOnly(true).Register(cmd)
+
+	output, _ := parseCompletion(cmd, []string{"__complete", "--level", ""})
+	candidates, _ := parseCompletionLines(output)
+	assert.Equal(t, []string{"debug", "info"}, candidates)
+
+	output, _ = parseCompletion(cmd, []string{"__complete", "--lev"})
+	candidates, _ = parseCompletionLines(output)
+	assert.Contains(t, candidates, "--level")
+	assert.Contains(t, candidates, "--levels")
+}
+
+func TestCompletionDeprecated(t *testing.T) {
+	cmd := NewCmd("test").EnableCompletion()
+	NewString("output").SetDeprecatedAlias("out", "use --output instead").SetOptional(true).SetFlagOnly(true).Register(cmd)
+	NewBool("old").SetDeprecated("it has no effect").Register(cmd)
+	cmd.RegisterCmd(NewCmd("remove"))
+	cmd.RegisterCmd(NewCmd("rmdir").SetDeprecated("use 'remove' instead"))
+
+	output, _ := parseCompletion(cmd, []string{"__complete", "--o"})
+	candidates, _ := parseCompletionLines(output)
+	assert.Equal(t, []string{"--output"}, candidates)
+
+	output, _ = parseCompletion(cmd, []string{"__complete", "r"})
+	candidates, _ = parseCompletionLines(output)
+	assert.Equal(t, []string{"remove"}, candidates)
+}
+
+func TestCompletionContextFunc(t *testing.T) {
+	type ctxKey struct{}
+	cmd := NewCmd("test").EnableCompletion()
+	_, err := NewString("region").SetOptional(true).
+		SetContextCompletionFunc(func(ctx context.Context, toComplete string) ([]string, CompletionDirective) {
+			return []string{ctx.Value(ctxKey{}).(string)}, CompletionDirectiveNoFileComp
+		}).Register(cmd)
+	require.NoError(t, err)
+	_, err = NewString("zone").SetOptional(true).
+		SetContextCompletionFunc(func(ctx context.Context, toComplete string) ([]string, CompletionDirective) {
+			assert.NoError(t, ctx.Err())
+			return []string{"a"}, CompletionDirectiveNoFileComp
+		}).Register(cmd)
+	require.NoError(t, err)
+
+	cleanup, exitCode, stdout, _ := mockExit(t)
+	defer cleanup()
+	ctx := context.WithValue(context.Background(), ctxKey{}, "eu-west")
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		cmd.ExecuteContext(ctx, []string{"__complete", "--region", ""})
+	})
+	assert.Equal(t, 0, *exitCode)
+	candidates, directive := parseCompletionLines(stdout.String())
+	assert.Equal(t, []string{"eu-west"}, candidates)
+	assert.Equal(t, ":4", directive)
+
+	// Outside ExecuteContext, the context is context.Background()
+	out, err := parseCompletion(cmd, []string{"__complete", "--zone", ""})
+	assert.ErrorIs(t, err, CompletionInvokedErr)
+	candidates, _ = parseCompletionLines(out)
+	assert.Equal(t, []string{"a"}, candidates)
+}
@@ -0,0 +1,188 @@
+package ra
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Condition switches a conditional constraint on, depending on the values other
+// flags end up with after parsing. It either compares a flag's value, or calls a
+// custom test.
+type Condition struct {
+	Flag  string              // with Value, holds when this flag's value is Value
+	Value string              // compared against the flag's value as formatted in help
+	Desc  string              // with Test, describes the condition in help, e.g. "running in CI"
+	Test  func(cmd *Cmd) bool // if set, holds when it returns true
+}
+
+// ConditionalExcludes is an exclusion that only applies while When holds.
+type ConditionalExcludes struct {
+	Flags []string
+	When  Condition
+}
+
+// String describes the condition for help and dump, e.g. "mode=remote".
+func (cond Condition) String() string {
+	if cond.Test != nil {
+		return cond.Desc
+	}
+	return cond.Flag + "=" + cond.Value
+}
+
+// describe phrases the condition for errors, e.g. "'mode' is 'remote'".
+func (cond Condition) describe() string {
+	if cond.Test != nil {
+		return cond.Desc
+	}
+	return fmt.Sprintf("'%s' is '%s'", cond.Flag, cond.Value)
+}
+
+// conditionHolds evaluates cond against the command's parsed values.
+func (c *Cmd) conditionHolds(cond Condition) bool {
+	if cond.Test != nil {
+		return cond.Test(c)
+	}
+	return slices.Contains(c.flagValueStrings(c.resolveFlagAlias(cond.Flag)), cond.Value)
+}
+
+// flagValueStrings returns a flag's value, or each element of a slice, formatted
+// as in help. Flags with no value (neither set nor defaulted) return nil, except
+// bools, which are always true or false.
+func (c *Cmd) flagValueStrings(name string) []string {
+	flag, exists := c.flags[name]
+	if !exists {
+		return nil
+	}
+	if f, ok := flag.(*BoolFlag); ok {
+		return []string{strconv.FormatBool(f.Value != nil && *f.Value)}
+	}
+	if !c.flagHasValue(name) {
+		return nil
+	}
+
+	switch f := flag.(type) {
+	case *StringFlag:
+		return []string{*f.Value}
+	case *IntFlag:
+		return []string{strconv.Itoa(*f.Value)}
+	case *Int64Flag:
+		return []string{strconv.FormatInt(*f.Value, 10)}
+	case *Float64Flag:
+		return []string{strconv.FormatFloat(*f.Value, 'g', -1, 64)}
+	case *StringSliceFlag:
+		return *f.Value
+	case *IntSliceFlag:
+		return formatEach(*f.Value, strconv.Itoa)
+	case *Int64SliceFlag:
+		return formatEach(*f.Value, func(v int64) string { return strconv.FormatInt(v, 10) })
+	case *Float64SliceFlag:
+		return formatEach(*f.Value, func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) })
+	case *BoolSliceFlag:
+		return formatEach(*f.Value, strconv.FormatBool)
+	case valueFlag:
+		return f.valueStrings()
+	}
+	return nil
+}
+
+// isOptional reports whether a flag may be left out when none of its RequiredIf
+// conditions hold: it's optional, or only required under those conditions.
+func isOptional(base *BaseFlag) bool {
+	return base.Optional || len(base.RequiredIf) > 0
+}
+
+// formatEach formats every element of values.
+func formatEach[T any](values []T, format func(T) string) []string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = format(v)
+	}
+	return strs
+}
+
+// checkConditionalConstraints checks the flag's RequiredIf and ExcludesIf
+// constraints, reporting errors like checkExclusion.
+func (c *Cmd) checkConditionalConstraints(flagName string) error {
+	base := getBaseFlag(c.flags[flagName])
+	if base == nil {
+		return nil
+	}
+
+	if !c.flagConfiguredForRelationalConstraints(flagName) {
+		for _, cond := range base.RequiredIf {
+			if c.conditionHolds(cond) {
+				return newParseError(MissingRequired, flagName, "", "Invalid args: '%s' is required when %s", flagName, cond.describe())
+			}
+		}
+	}
+
+	if !c.flagExplicitlySetForExclusion(flagName) {
+		return nil
+	}
+	for _, excl := range base.ExcludesIf {
+		if !c.conditionHolds(excl.When) {
+			continue
+		}
+		for _, excluded := range excl.Flags {
+			if c.flagExplicitlySetForExclusion(excluded) {
+				return newParseError(Excluded, flagName, "",
+					"Invalid args: '%s' excludes '%s' when %s, but '%s' was set",
+					flagName,
+					excluded,
+					excl.When.describe(),
+					excluded,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// validateConditionReferences checks that conditional constraints name
+// registered flags.
+func (c *Cmd) validateConditionReferences(base *BaseFlag, validFlags map[string]bool) error {
+	conditions := slices.Clone(base.RequiredIf)
+	for _, excl := range base.ExcludesIf {
+		conditions = append(conditions, excl.When)
+		for _, name := range excl.Flags {
+			if !validFlags[name] {
+				return NewProgrammingError(fmt.Sprintf("Undefined flag '%s'", name))
+			}
+		}
+	}
+	for _, cond := range conditions {
+		if cond.Test == nil && !validFlags[cond.Flag] {
+			return NewProgrammingError(fmt.Sprintf("Undefined flag '%s'", cond.Flag))
+		}
+	}
+	return nil
+}
+
+// getRequiredIfString describes when the flag is required, for help.
+func (c *Cmd) getRequiredIfString(flag any) string {
+	base := getBaseFlag(flag)
+	if base == nil || len(base.RequiredIf) == 0 {
+		return ""
+	}
+	conditions := make([]string, len(base.RequiredIf))
+	for i, cond := range base.RequiredIf {
+		conditions[i] = cond.String()
+	}
+	return strings.Join(conditions, " or ")
+}
+
+// getExcludesIfStrings describes the flag's conditional exclusions for help,
+// e.g. "socket (if protocol=tcp)".
+func (c *Cmd) getExcludesIfStrings(flag any) []string {
+	base := getBaseFlag(flag)
+	if base == nil {
+		return nil
+	}
+	var parts []string
+	for _, excl := range base.ExcludesIf {
+		parts = append(parts, fmt.Sprintf("%s (if %s)", strings.Join(excl.Flags, ", "), excl.When))
+	}
+	return parts
+}
@@ -0,0 +1,366 @@
+package ra
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigDecoder decodes raw config file contents into v. json.Unmarshal, yaml.Unmarshal
+// and toml.Unmarshal all satisfy this signature.
+type ConfigDecoder func(data []byte, v any) error
+
+// WithConfigFile loads flag values from the given config file. Keys are flag names;
+// nested tables/objects keyed by a subcommand name hold that subcommand's flags.
+// Only flags not set on the command line or from the environment are filled, so
+// precedence is command line > environment > config > Default.
+//
+// JSON files are decoded out of the box; other formats need WithConfigDecoder.
+// A file that doesn't exist is ignored, so a conventional path can always be passed.
+func WithConfigFile(path string) ParseOpt {
+	return func(c *parseCfg) {
+		c.configPath = path
+	}
+}
+
+// WithConfigDecoder sets the decoder used for the file given to WithConfigFile.
+func WithConfigDecoder(decoder ConfigDecoder) ParseOpt {
+	return func(c *parseCfg) {
+		c.configDecoder = decoder
+	}
+}
+
+// WithConfigValues supplies already-decoded config values, laid out the same way
+// as a config file. Takes precedence over WithConfigFile.
+func WithConfigValues(values map[string]any) ParseOpt {
+	return func(c *parseCfg) {
+		c.configValues = values
+	}
+}
+
+// WithStrictConfig makes config keys that match no flag or subcommand an error.
+func WithStrictConfig(strict bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.strictConfig = strict
+	}
+}
+
+// withConfigSection hands a subcommand its already-loaded section of the config.
+func withConfigSection(section map[string]any, path string) ParseOpt {
+	return func(c *parseCfg) {
+		c.configLoaded = true
+		c.configSection = section
+		c.configKeyPrefix = path
+	}
+}
+
+// loadConfig resolves the config tree for this parse, once, at the root command.
+func (c *Cmd) loadConfig(cfg *parseCfg) error {
+	if cfg.configLoaded {
+		return nil
+	}
+	cfg.configLoaded = true
+
+	var raw map[string]any
+	if cfg.configValues != nil {
+		raw = cfg.configValues
+	} else if cfg.configPath != "" {
+		data, err := os.ReadFile(cfg.configPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return newParseError(InvalidConfig, "", "", "failed to read config file %s: %v", cfg.configPath, err)
+		}
+
+		decoder := cfg.configDecoder
+		if decoder == nil {
+			if strings.ToLower(filepath.Ext(cfg.configPath)) != ".json" {
+				return NewProgrammingError(fmt.Sprintf(
+					"no decoder for config file %q (only JSON is built in, set one with WithConfigDecoder)",
+					cfg.configPath,
+				))
+			}
+			decoder = decodeJSON
+		}
+
+		if err := decoder(data, &raw); err != nil {
+			return newParseError(InvalidConfig, "", "", "failed to parse config file %s: %v", cfg.configPath, err)
+		}
+	} else {
+		return nil
+	}
+
+	section, err := normalizeConfigMap(raw, "")
+	if err != nil {
+		return err
+	}
+
+	if cfg.strictConfig {
+		if err := c.checkConfigKeys(section, "", nil); err != nil {
+			return err
+		}
+	}
+
+	cfg.configSection = section
+	return nil
+}
+
+// decodeJSON is the built-in decoder. It keeps numbers as json.Number so integers
+// beyond float64's precision reach integer flags intact.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	// Like json.Unmarshal, reject anything after the value
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after top-level value")
+	}
+	return nil
+}
+
+// normalizeConfigMap converts nested maps to map[string]any, since some decoders
+// (e.g. yaml.v2) produce map[any]any.
+func normalizeConfigMap(m map[string]any, path string) (map[string]any, error) {
+	out := make(map[string]any, len(m))
+	for key, value := range m {
+		normalized, err := normalizeConfigValue(value, joinConfigKey(path, key))
+		if err != nil {
+			return nil, err
+		}
+		out[key] = normalized
+	}
+	return out, nil
+}
+
+func normalizeConfigValue(value any, path string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		return normalizeConfigMap(v, path)
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = val
+		}
+		return normalizeConfigMap(m, path)
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			normalized, err := normalizeConfigValue(elem, path)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = normalized
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// checkConfigKeys reports the first (in sorted order) config key that matches no
+// flag or subcommand, by name or alias. inherited holds the names and aliases of
+// global flags from ancestor commands, which may not have been applied to this
+// command yet.
+func (c *Cmd) checkConfigKeys(section map[string]any, path string, inherited map[string]bool) error {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	globals := make(map[string]bool, len(inherited)+len(c.globalFlags))
+	for name := range inherited {
+		globals[name] = true
+	}
+	for _, name := range c.globalFlags {
+		globals[name] = true
+		if base := getBaseFlag(c.globalFlag(name)); base != nil {
+			for _, alias := range base.Aliases {
+				globals[alias] = true
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if subCmd, exists := c.lookupSubCmd(key); exists {
+			if sub, ok := section[key].(map[string]any); ok {
+				if err := subCmd.checkConfigKeys(sub, joinConfigKey(path, key), globals); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if _, exists := c.flags[c.resolveFlagAlias(key)]; exists || globals[key] {
+			continue
+		}
+		return newParseError(InvalidConfig, "", "", "unknown config key: %s", joinConfigKey(path, key))
+	}
+	return nil
+}
+
+// configSectionFor returns the config section for an invoked subcommand: its own
+// nested section, keyed by its name or else an alias, plus any values given for
+// this command's global flags.
+func (c *Cmd) configSectionFor(section map[string]any, subCmd *Cmd) map[string]any {
+	if section == nil {
+		return nil
+	}
+
+	sub := make(map[string]any)
+	for _, name := range append([]string{subCmd.name}, subCmd.aliases...) {
+		if own, ok := section[name].(map[string]any); ok {
+			for key, value := range own {
+				if _, exists := sub[key]; !exists {
+					sub[key] = value
+				}
+			}
+		}
+	}
+	for _, globalFlagName := range c.globalFlags {
+		flag := c.globalFlag(globalFlagName)
+		if key, value, ok := configValue(section, globalFlagName, flag); ok {
+			if _, _, exists := configValue(sub, globalFlagName, flag); !exists {
+				sub[key] = value
+			}
+		}
+	}
+	return sub
+}
+
+// configValue looks up the value for a flag in a config section, by its name or
+// else one of its aliases, returning the key it was found under.
+func configValue(section map[string]any, name string, flag any) (string, any, bool) {
+	if value, ok := section[name]; ok {
+		return name, value, true
+	}
+	if base := getBaseFlag(flag); base != nil {
+		for _, alias := range base.Aliases {
+			if value, ok := section[alias]; ok {
+				return alias, value, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// applyConfigValues assigns flags that are still unset from the config section,
+// through the same conversion and constraint checks as command-line input. Like
+// environment values, config values count as configured. Flags for which skip
+// returns true are left alone.
+func (c *Cmd) applyConfigValues(section map[string]any, path string, skip func(name string) bool) error {
+	if section == nil {
+		return nil
+	}
+
+	for _, name := range c.getAllFlagsInRegistrationOrder() {
+		if c.configured[name] || (skip != nil && skip(name)) {
+			continue
+		}
+
+		flag, exists := c.flags[name]
+		if !exists {
+			continue
+		}
+
+		configKey, value, ok := configValue(section, name, flag)
+		if !ok || value == nil {
+			continue
+		}
+		if _, isSection := value.(map[string]any); isSection {
+			if _, isSubCmd := c.lookupSubCmd(configKey); isSubCmd {
+				continue
+			}
+		}
+
+		key := joinConfigKey(path, configKey)
+		values, err := configValueStrings(value, key)
+		if err != nil {
+			return err
+		}
+		if len(values) > 1 && !isSliceFlag(flag) {
+			return newParseError(InvalidConfig, name, "", "config key %s must be a single value, got a list", key)
+		}
+
+		for _, v := range values {
+			if err := c.setFlagFromString(flag, v); err != nil {
+				if _, ok := err.(*ProgrammingError); ok {
+					return err
+				}
+				return fmt.Errorf("%w (from config key %s)", err, key)
+			}
+		}
+		c.setSource(name, SourceConfig, key, values)
+	}
+	return nil
+}
+
+// configValueStrings renders a decoded config value as the string token(s) a user
+// would have typed on the command line.
+func configValueStrings(value any, key string) ([]string, error) {
+	// An object fills a map flag, as key=value entries in key order
+	if obj, ok := value.(map[string]any); ok {
+		values := make([]string, 0, len(obj))
+		for _, k := range sortedKeys(obj) {
+			s, err := configScalarString(obj[k], key)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, k+"="+s)
+		}
+		return values, nil
+	}
+
+	if list, ok := value.([]any); ok {
+		values := make([]string, 0, len(list))
+		for _, elem := range list {
+			s, err := configScalarString(elem, key)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	}
+
+	s, err := configScalarString(value, key)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+func configScalarString(value any, key string) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case json.Number:
+		return v.String(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	return "", newParseError(InvalidConfig, "", fmt.Sprint(value), "unsupported value for config key %s: %v", key, value)
+}
+
+func joinConfigKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,255 @@
+package ra
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
+
+func Test_Config_JSONFileFillsFlags(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{"port": 9090, "host": "example.com", "verbose": true, "ratio": 0.5}`)
+	cmd := NewCmd("app")
+
+	port, _ := NewInt("port").Register(cmd)
+	host, _ := NewString("host").Register(cmd)
+	verbose, _ := NewBool("verbose").Register(cmd)
+	ratio, _ := NewFloat64("ratio").Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(path))
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, *port)
+	assert.Equal(t, "example.com", *host)
+	assert.True(t, *verbose)
+	assert.Equal(t, 0.5, *ratio)
+	assert.True(t, cmd.Configured("port"))
+}
+
+func Test_Config_JSONLargeIntegers(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{"id": 9007199254740993, "ids": [9007199254740993], "ratio": 1.5}`)
+	cmd := NewCmd("app")
+
+	id, _ := NewInt64("id").Register(cmd)
+	ids, _ := NewInt64Slice("ids").Register(cmd)
+	ratio, _ := NewFloat64("ratio").Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(path))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), *id)
+	assert.Equal(t, []int64{9007199254740993}, *ids)
+	assert.Equal(t, 1.5, *ratio)
+
+	path = writeConfigFile(t, "trailing.json", `{"id": 1} {}`)
+	err = NewCmd("app").ParseOrError([]string{}, WithConfigFile(path))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse config file")
+}
+
+func Test_Config_Precedence(t *testing.T) {
+	t.Setenv("APP_REGION", "env-region")
+	config := map[string]any{"port": 1111, "region": "config-region", "zone": "config-zone"}
+	cmd := NewCmd("app")
+
+	port, _ := NewInt("port").SetDefault(80).Register(cmd)
+	region, _ := NewString("region").SetEnv("APP_REGION").Register(cmd)
+	zone, _ := NewString("zone").SetDefault("default-zone").Register(cmd)
+	name, _ := NewString("name").SetDefault("default-name").Register(cmd)
+
+	err := cmd.ParseOrError([]string{"--port", "2222"}, WithConfigValues(config))
+	assert.NoError(t, err)
+	assert.Equal(t, 2222, *port)
+	assert.Equal(t, "env-region", *region)
+	assert.Equal(t, "config-zone", *zone)
+	assert.Equal(t, "default-name", *name)
+	assert.False(t, cmd.Configured("name"))
+}
+
+func Test_Config_ValuesAreValidated(t *testing.T) {
+	cmd := NewCmd("app")
+	_, _ = NewInt("port").SetMax(65535, true).Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigValues(map[string]any{"port": 70000}))
+	assert.EqualError(t, err, "'port' value 70000 is > maximum 65535 (from config key port)")
+
+	cmd = NewCmd("app")
+	_, _ = NewString("format").SetEnumConstraint([]string{"json", "yaml"}).Register(cmd)
+
+	err = cmd.ParseOrError([]string{}, WithConfigValues(map[string]any{"format": "csv"}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid 'format' value: csv")
+	assert.Contains(t, err.Error(), "(from config key format)")
+}
+
+func Test_Config_ListsFillSlices(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{"tags": ["a", "b"], "ids": [1, 2, 3], "name": ["x", "y"]}`)
+	cmd := NewCmd("app")
+
+	tags, _ := NewStringSlice("tags").SetDefault([]string{"default"}).Register(cmd)
+	ids, _ := NewIntSlice("ids").Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(path))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, *tags)
+	assert.Equal(t, []int{1, 2, 3}, *ids)
+
+	cmd = NewCmd("app")
+	_, _ = NewString("name").Register(cmd)
+	err = cmd.ParseOrError([]string{}, WithConfigFile(path))
+	assert.EqualError(t, err, "config key name must be a single value, got a list")
+}
+
+func Test_Config_SubcommandSections(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{
+		"profile": "prod",
+		"deploy": {"replicas": 3, "rollout": {"strategy": "canary"}}
+	}`)
+	cmd := NewCmd("app")
+	profile, _ := NewString("profile").SetOptional(true).Register(cmd, WithGlobal(true))
+
+	deploy := NewCmd("deploy")
+	replicas, _ := NewInt("replicas").Register(deploy)
+	_, _ = cmd.RegisterCmd(deploy)
+
+	rollout := NewCmd("rollout")
+	strategy, _ := NewString("strategy").Register(rollout)
+	_, _ = deploy.RegisterCmd(rollout)
+
+	err := cmd.ParseOrError([]string{"deploy", "rollout"}, WithConfigFile(path))
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", *profile)
+	assert.Equal(t, 3, *replicas)
+	assert.Equal(t, "canary", *strategy)
+}
+
+func Test_Config_StrictRejectsUnknownKeys(t *testing.T) {
+	config := map[string]any{
+		"port":   1,
+		"deploy": map[string]any{"replicas": 2, "replica": 3},
+	}
+	newCmd := func() *Cmd {
+		cmd := NewCmd("app")
+		_, _ = NewInt("port").Register(cmd)
+		deploy := NewCmd("deploy")
+		_, _ = NewInt("replicas").SetOptional(true).Register(deploy)
+		_, _ = cmd.RegisterCmd(deploy)
+		return cmd
+	}
+
+	err := newCmd().ParseOrError([]string{}, WithConfigValues(config))
+	assert.NoError(t, err)
+
+	err = newCmd().ParseOrError([]string{}, WithConfigValues(config), WithStrictConfig(true))
+	assert.EqualError(t, err, "unknown config key: deploy.replica")
+}
+
+func Test_Config_Aliases(t *testing.T) {
+	config := map[string]any{
+		"loud": true,
+		"dir":  "/srv",
+		"rm":   map[string]any{"yes": true},
+	}
+	type parsed struct {
+		verbose, force *bool
+		path           *string
+	}
+	newCmd := func() (*Cmd, parsed) {
+		var p parsed
+		cmd := NewCmd("app")
+		p.verbose, _ = NewBool("verbose").SetAliases("loud").Register(cmd, WithGlobal(true))
+		p.path, _ = NewString("path").SetAliases("dir").SetOptional(true).SetFlagOnly(true).Register(cmd)
+		remove := NewCmd("remove").SetAliases("rm")
+		p.force, _ = NewBool("force").SetAliases("yes").Register(remove)
+		_, _ = cmd.RegisterCmd(remove)
+		return cmd, p
+	}
+
+	cmd, p := newCmd()
+	err := cmd.ParseOrError([]string{"remove"}, WithConfigValues(config), WithStrictConfig(true))
+	assert.NoError(t, err)
+	assert.True(t, *p.verbose)
+	assert.Equal(t, "/srv", *p.path)
+	assert.True(t, *p.force)
+	assert.Equal(t, "dir", cmd.Source("path").Key)
+
+	// A value under the flag's name wins over one under an alias
+	cmd, p = newCmd()
+	err = cmd.ParseOrError([]string{}, WithConfigValues(map[string]any{"path": "/a", "dir": "/b"}), WithStrictConfig(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "/a", *p.path)
+
+	cmd, _ = newCmd()
+	err = cmd.ParseOrError([]string{}, WithConfigValues(map[string]any{"rm": map[string]any{"loud": true, "yess": true}}), WithStrictConfig(true))
+	assert.EqualError(t, err, "unknown config key: rm.yess")
+}
+
+func Test_Config_SatisfiesRequiredAndRelational(t *testing.T) {
+	cmd := NewCmd("app")
+	_, _ = NewString("password").SetOptional(true).SetRequires([]string{"user"}).Register(cmd)
+	_, _ = NewString("user").SetOptional(true).Register(cmd)
+	_, _ = NewString("token").SetFlagOnly(true).Register(cmd)
+
+	err := cmd.ParseOrError(
+		[]string{"--password", "pw"},
+		WithConfigValues(map[string]any{"user": "alice", "token": "t"}),
+	)
+	assert.NoError(t, err)
+}
+
+func Test_Config_MissingFileIgnored(t *testing.T) {
+	cmd := NewCmd("app")
+	port, _ := NewInt("port").SetDefault(80).Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(filepath.Join(t.TempDir(), "nope.json")))
+	assert.NoError(t, err)
+	assert.Equal(t, 80, *port)
+}
+
+func Test_Config_CustomDecoder(t *testing.T) {
+	path := writeConfigFile(t, "app.conf", "port=7000\nhost=local\n")
+	decoder := func(data []byte, v any) error {
+		m := map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			kv := strings.SplitN(line, "=", 2)
+			m[kv[0]] = kv[1]
+		}
+		*(v.(*map[string]any)) = m
+		return nil
+	}
+	cmd := NewCmd("app")
+	port, _ := NewInt("port").Register(cmd)
+	host, _ := NewString("host").Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(path), WithConfigDecoder(decoder))
+	assert.NoError(t, err)
+	assert.Equal(t, 7000, *port)
+	assert.Equal(t, "local", *host)
+}
+
+func Test_Config_NonJSONWithoutDecoderIsProgrammingError(t *testing.T) {
+	path := writeConfigFile(t, "app.yaml", "port: 1\n")
+	cmd := NewCmd("app")
+	_, _ = NewInt("port").SetOptional(true).Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(path))
+	var progErr *ProgrammingError
+	assert.ErrorAs(t, err, &progErr)
+}
+
+func Test_Config_MalformedFile(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{"port": `)
+	cmd := NewCmd("app")
+	_, _ = NewInt("port").SetOptional(true).Register(cmd)
+
+	err := cmd.ParseOrError([]string{}, WithConfigFile(path))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse config file")
+}
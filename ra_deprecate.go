@@ -0,0 +1,96 @@
+package ra
+
+import (
+	"fmt"
+	"slices"
+)
+
+// noteDeprecatedFlag records a deprecation warning if the flag about to be marked
+// configured from the command line is deprecated.
+func (c *Cmd) noteDeprecatedFlag(name string) {
+	base := getBaseFlag(c.flags[name])
+	if base != nil && base.Deprecated != "" {
+		c.noteDeprecation(fmt.Sprintf("flag --%s is deprecated: %s", name, base.Deprecated))
+	}
+}
+
+// noteDeprecatedFlagAlias records a deprecation warning if alias, as typed on the
+// command line, is a deprecated alias of the flag named name.
+func (c *Cmd) noteDeprecatedFlagAlias(alias, name string) {
+	base := getBaseFlag(c.flags[name])
+	if base == nil {
+		return
+	}
+	if msg, ok := base.DeprecatedAliases[alias]; ok {
+		c.noteDeprecation(fmt.Sprintf("flag --%s is deprecated: %s", alias, msg))
+	}
+}
+
+// noteDeprecatedSubCmd records deprecation warnings for a subcommand invoked by
+// name, if it or the alias used is deprecated.
+func (c *Cmd) noteDeprecatedSubCmd(subCmd *Cmd, invokedAs string) {
+	if msg, ok := subCmd.deprecatedAliases[invokedAs]; ok {
+		c.noteDeprecation(fmt.Sprintf("command %q is deprecated: %s", invokedAs, msg))
+	}
+	if subCmd.deprecated != "" {
+		c.noteDeprecation(fmt.Sprintf("command %q is deprecated: %s", subCmd.name, subCmd.deprecated))
+	}
+}
+
+// noteDeprecation queues a warning, once per parse, until reportDeprecations.
+func (c *Cmd) noteDeprecation(warning string) {
+	if !slices.Contains(c.deprecations, warning) {
+		c.deprecations = append(c.deprecations, warning)
+	}
+}
+
+// reportDeprecations passes queued warnings to the Deprecated parse hook, or writes
+// them to stderr. With WithDeprecationErrors, the first is returned as an error.
+func (c *Cmd) reportDeprecations(cfg *parseCfg) error {
+	warnings := c.deprecations
+	c.deprecations = nil
+	for _, warning := range warnings {
+		if cfg.deprecationErrors {
+			return newParseError(DeprecatedUse, "", "", "%s", warning)
+		}
+		if cfg.deprecationHook != nil {
+			cfg.deprecationHook(c, warning)
+		} else {
+			fmt.Fprintf(stderrWriter, "Warning: %s\n", warning)
+		}
+	}
+	return nil
+}
+
+// addDeprecatedAlias adds alias to the flag's aliases, warning with msg when used.
+func (b *BaseFlag) addDeprecatedAlias(alias, msg string) {
+	if !slices.Contains(b.Aliases, alias) {
+		b.Aliases = append(b.Aliases, alias)
+	}
+	if b.DeprecatedAliases == nil {
+		b.DeprecatedAliases = make(map[string]string)
+	}
+	b.DeprecatedAliases[alias] = msg
+}
+
+// visibleAliases returns a flag's aliases that aren't deprecated.
+func (b *BaseFlag) visibleAliases() []string {
+	var aliases []string
+	for _, alias := range b.Aliases {
+		if _, deprecated := b.DeprecatedAliases[alias]; !deprecated {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// visibleAliases returns a command's aliases that aren't deprecated.
+func (c *Cmd) visibleAliases() []string {
+	var aliases []string
+	for _, alias := range c.aliases {
+		if _, deprecated := c.deprecatedAliases[alias]; !deprecated {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
@@ -91,6 +91,12 @@ func (c *Cmd) generateParseConfigSectionWithIndent(depth int, opts ...ParseOpt)
 
 	sb.WriteString(fmt.Sprintf("%s  Ignore Unknown: %s\n", indent, BoldS(fmt.Sprintf("%t", cfg.ignoreUnknown))))
 	sb.WriteString(fmt.Sprintf("%s  Dump Enabled: %s\n", indent, BoldS(fmt.Sprintf("%t", cfg.dump))))
+	if cfg.configPath != "" {
+		sb.WriteString(fmt.Sprintf("%s  Config File: %s\n", indent, BoldS(cfg.configPath)))
+	}
+	if cfg.strictConfig {
+		sb.WriteString(fmt.Sprintf("%s  Strict Config: %s\n", indent, BoldS("true")))
+	}
 	sb.WriteString("\n")
 
 	return sb.String()
@@ -119,6 +125,9 @@ func (c *Cmd) generateCommandInfoSectionWithIndent(depth int) string {
 	sb.WriteString(fmt.Sprintf("%s  Auto Help on No Args: %s\n", indent, BoldS(fmt.Sprintf("%t", c.autoHelpOnNoArgs))))
 	sb.WriteString(fmt.Sprintf("%s  Hidden: %s\n", indent, BoldS(fmt.Sprintf("%t", c.hidden))))
 	sb.WriteString(fmt.Sprintf("%s  Hidden in Short Help: %s\n", indent, BoldS(fmt.Sprintf("%t", c.hiddenInShortHelp))))
+	if c.deprecated != "" {
+		sb.WriteString(fmt.Sprintf("%s  Deprecated: %s\n", indent, BoldS(c.deprecated)))
+	}
 
 	if c.customUsage != nil {
 		sb.WriteString(fmt.Sprintf("%s  Custom Usage Function: %s\n", indent, BoldS("set")))
@@ -219,6 +228,15 @@ func (c *Cmd) generateFlagsStructureSectionWithIndent(depth int) string {
 		sb.WriteString("\n")
 	}
 
+	// Flag groups
+	if len(c.flagGroups) > 0 {
+		sb.WriteString(fmt.Sprintf("%s%s\n", indent, GreenBoldS("  Flag Groups:")))
+		for _, group := range c.flagGroups {
+			sb.WriteString(fmt.Sprintf("%s    %s: %s\n", indent, group.Kind, BoldS(strings.Join(group.Flags, ", "))))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Flag conflicts and shadows
 	if len(c.overriddenGlobalFlags) > 0 || len(c.shadowedShortFlags) > 0 || len(c.shadowedNameFlags) > 0 {
 		sb.WriteString(fmt.Sprintf("%s%s\n", indent, GreenBoldS("  Flag Conflicts:")))
@@ -299,7 +317,7 @@ func (c *Cmd) formatFlagForDump(name string, flag any) string {
 	parts = append(parts, fmt.Sprintf("type:%s", CyanS(flagType)))
 
 	// Required/Optional with default value
-	if base.Optional {
+	if isOptional(base) {
 		parts = append(parts, CyanS("optional"))
 	} else {
 		hasDefault := c.flagHasDefault(flag)
@@ -320,6 +338,14 @@ func (c *Cmd) formatFlagForDump(name string, flag any) string {
 	// Configured status
 	if c.configured[name] {
 		parts = append(parts, "configured")
+		if source, exists := c.sources[name]; exists {
+			parts = append(parts, formatSourceForDump(source))
+		}
+	}
+
+	// Environment variable binding
+	if base.Env != "" {
+		parts = append(parts, fmt.Sprintf("env:%s", base.Env))
 	}
 
 	// Relational constraints
@@ -333,6 +359,14 @@ func (c *Cmd) formatFlagForDump(name string, flag any) string {
 		parts = append(parts, fmt.Sprintf("excludes:[%s]", strings.Join(excludes, ",")))
 	}
 
+	// Conditional constraints
+	if requiredIf := c.getRequiredIfString(flag); requiredIf != "" {
+		parts = append(parts, fmt.Sprintf("required-if:[%s]", requiredIf))
+	}
+	for _, excl := range base.ExcludesIf {
+		parts = append(parts, fmt.Sprintf("excludes-if(%s):[%s]", excl.When, strings.Join(excl.Flags, ",")))
+	}
+
 	// Flag properties
 	var flags []string
 	if base.Hidden {
@@ -341,6 +375,9 @@ func (c *Cmd) formatFlagForDump(name string, flag any) string {
 	if base.HiddenInShortHelp {
 		flags = append(flags, "hidden-in-short")
 	}
+	if base.Deprecated != "" {
+		flags = append(flags, "deprecated")
+	}
 	if base.PositionalOnly {
 		flags = append(flags, "positional-only")
 	}
@@ -503,6 +540,8 @@ func (c *Cmd) getFlagTypeForDump(flag any) string {
 			result += fmt.Sprintf(" sep:%q", *f.Separator)
 		}
 		return result
+	case valueFlag:
+		return f.dumpType()
 	}
 	return "unknown"
 }
@@ -556,6 +595,8 @@ func (c *Cmd) getFlagDefaultValue(flag any) string {
 			return fmt.Sprintf("%v", *f.Default)
 		}
 		return "[]"
+	case valueFlag:
+		return f.dumpDefault()
 	}
 	return "none"
 }
@@ -604,6 +645,8 @@ func (c *Cmd) getFlagCurrentValue(flag any) string {
 		if f.Value != nil && len(*f.Value) > 0 {
 			return fmt.Sprintf("%v", *f.Value)
 		}
+	case valueFlag:
+		return f.dumpCurrent()
 	}
 	return ""
 }
@@ -651,6 +694,10 @@ func (c *Cmd) getFlagRequires(flag any) []string {
 		if f.Requires != nil {
 			return *f.Requires
 		}
+	case valueFlag:
+		if base := f.getBase(); base.Requires != nil {
+			return *base.Requires
+		}
 	}
 	return nil
 }
@@ -698,6 +745,20 @@ func (c *Cmd) getFlagExcludes(flag any) []string {
 		if f.Excludes != nil {
 			return *f.Excludes
 		}
+	case valueFlag:
+		if base := f.getBase(); base.Excludes != nil {
+			return *base.Excludes
+		}
 	}
 	return nil
 }
+
+// formatSourceForDump renders where a configured flag's value came from, e.g.
+// source:flag(--port 8080) or source:env(APP_PORT)
+func formatSourceForDump(source *ValueSource) string {
+	detail := source.Key
+	if source.Kind == SourceFlag || source.Kind == SourcePositional {
+		detail = strings.Join(source.Tokens, " ")
+	}
+	return fmt.Sprintf("source:%s(%s)", source.Kind, detail)
+}
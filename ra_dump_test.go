@@ -3,8 +3,10 @@ package ra
 import (
 	"bytes"
 	"errors"
+	"net/netip"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -394,3 +396,162 @@ Environment:
 
 	assert.Equal(t, expected, stdout.String())
 }
+
+func TestDumpShowsValueSources(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+	t.Setenv("TEST_REGION", "eu-west-1")
+
+	cmd := NewCmd("sources")
+
+	_, err := NewString("file").SetUsage("Input file").Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewInt("port").SetShort("p").SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewString("region").SetFlagOnly(true).SetEnv("TEST_REGION").Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{"in.txt", "-p", "8080"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, `file type:string required current:"in.txt" configured source:positional(in.txt)`)
+	assert.Contains(t, dump, "port (-p) type:int required current:8080 configured source:flag(-p 8080)")
+	assert.Contains(t, dump, "configured source:env(TEST_REGION) env:TEST_REGION")
+}
+
+func TestDumpCustomFlags(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("custom")
+
+	_, err := NewCustom("addr", netip.ParseAddr, netip.Addr.String).
+		SetDefault(netip.MustParseAddr("127.0.0.1")).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewCustomSlice("peers", netip.ParseAddr, netip.Addr.String).
+		SetSeparator(",").
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{"--addr", "10.0.0.1", "--peers", "10.0.0.2,10.0.0.3"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, "addr type:netip.Addr optional (default:127.0.0.1) current:10.0.0.1 configured")
+	assert.Contains(t, dump, `peers type:[]netip.Addr sep:"," required current:[10.0.0.2 10.0.0.3] configured`)
+}
+
+func TestDumpDurationAndTimeFlags(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("poll")
+
+	_, err := NewDuration("interval").
+		SetDefault(time.Minute).
+		SetMin(time.Second, true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewDurationSlice("backoff").
+		SetMax(time.Hour, false).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewTime("since", time.DateOnly).
+		SetRelative(true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{"--interval", "90s", "--backoff", "5s", "--since", "2024-03-01"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, "interval type:duration[1s,+∞) optional (default:1m) current:1m30s configured")
+	assert.Contains(t, dump, "backoff type:[]duration(-∞,1h) required current:[5s] configured")
+	assert.Contains(t, dump, "since type:time(relative) required current:2024-03-01 configured")
+}
+
+func TestDumpSizedNumberFlags(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("serve")
+
+	_, err := NewUint16("port").SetDefault(8080).SetMin(1, true).SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewInt8Slice("levels").SetSeparator(",").SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{"--port", "443", "--levels", "-1,2"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, "port type:uint16[1,+∞) optional (default:8080) current:443 configured")
+	assert.Contains(t, dump, `levels type:[]int8 sep:"," required current:[-1 2] configured`)
+}
+
+func TestDumpByteSizeFlag(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("cache")
+	_, err := NewByteSize("max-size").SetDefault(64*MiB).SetMax(GiB, false).SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{"--max-size", "1.5MB"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, "max-size type:bytesize(-∞,1GiB) optional (default:64MiB) current:1.5MB configured")
+}
+
+func TestDumpMapFlags(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("deploy")
+	_, err := NewStringMap("label").SetKeyEnumConstraint([]string{"env", "team"}).SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{"--label", "team=core,env=prod"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, `label type:map[string]string{env,team} sep:"," required current:[env=prod team=core] configured`)
+}
+
+func TestDumpFlagGroups(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("fetch")
+	_, err := NewString("file").SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewString("url").SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+	cmd.AddFlagGroup(ExactlyOne("file", "url"))
+
+	args := []string{"--url", "x"}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, "Flag Groups:\n    exactly one: file, url\n")
+}
+
+func TestDumpConditionalConstraints(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+
+	cmd := NewCmd("connect")
+	_, err := NewString("mode").SetDefault("local").SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewString("host").SetRequiredIf("mode", "remote").SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewInt("port").SetExcludesIf([]string{"host"}, "mode", "local").SetOptional(true).SetFlagOnly(true).Register(cmd)
+	assert.NoError(t, err)
+
+	args := []string{}
+	assert.NoError(t, cmd.ParseOrError(args))
+
+	dump := cmd.GenerateDump(args)
+	assert.Contains(t, dump, "required-if:[mode=remote]")
+	assert.Contains(t, dump, "excludes-if(mode=local):[host]")
+}
@@ -0,0 +1,52 @@
+package ra
+
+import (
+	"fmt"
+	"os"
+)
+
+// applyEnvValues assigns flags that weren't set on the command line from their
+// Env variables, ahead of any Default. Values go through the same conversion and
+// constraint checks as command-line input, and count as configured so that
+// required and relational validation see them. Empty variables are treated as
+// unset. Flags for which skip returns true are left alone.
+func (c *Cmd) applyEnvValues(skip func(name string) bool) error {
+	for _, name := range c.getAllFlagsInRegistrationOrder() {
+		if c.configured[name] || (skip != nil && skip(name)) {
+			continue
+		}
+
+		flag, exists := c.flags[name]
+		if !exists {
+			continue
+		}
+		base := getBaseFlag(flag)
+		if base == nil || base.Env == "" {
+			continue
+		}
+
+		value, ok := os.LookupEnv(base.Env)
+		if !ok || value == "" {
+			continue
+		}
+
+		if err := c.setFlagFromString(flag, value); err != nil {
+			if _, ok := err.(*ProgrammingError); ok {
+				return err
+			}
+			return fmt.Errorf("%w (from environment variable %s)", err, base.Env)
+		}
+		c.setSource(name, SourceEnv, base.Env, []string{value})
+	}
+	return nil
+}
+
+// isGlobalFlag reports whether the named flag was registered as (or inherited as) a global flag.
+func (c *Cmd) isGlobalFlag(name string) bool {
+	for _, globalFlagName := range c.globalFlags {
+		if globalFlagName == name {
+			return true
+		}
+	}
+	return false
+}
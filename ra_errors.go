@@ -0,0 +1,176 @@
+package ra
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseErrorKind classifies a ParseError, e.g. for tools that report errors as
+// JSON or translate them.
+type ParseErrorKind int
+
+const (
+	UnknownFlag           ParseErrorKind = iota + 1 // flag not defined on the command
+	UnknownSubcommand                               // argument looks like a mistyped subcommand
+	AmbiguousAbbreviation                           // prefix matches several flags or subcommands
+	MissingValue                                    // flag needs a value but none was given
+	InvalidValue                                    // value can't be converted to the flag's type
+	ConstraintViolation                             // value fails enum, regex, min/max, key or validator checks
+	MissingRequired                                 // required flag, or one required by a condition or group, not set
+	Excluded                                        // flag set alongside one it excludes
+	RequiresMissing                                 // flag set without one it requires
+	UnexpectedArg                                   // positional argument with no flag left to take it
+	DeprecatedUse                                   // deprecated flag or command used with WithDeprecationErrors
+	InvalidConfig                                   // config file unreadable, or has an unknown key or bad value
+)
+
+func (k ParseErrorKind) String() string {
+	switch k {
+	case UnknownFlag:
+		return "unknown flag"
+	case UnknownSubcommand:
+		return "unknown subcommand"
+	case AmbiguousAbbreviation:
+		return "ambiguous abbreviation"
+	case MissingValue:
+		return "missing value"
+	case InvalidValue:
+		return "invalid value"
+	case ConstraintViolation:
+		return "constraint violation"
+	case MissingRequired:
+		return "missing required"
+	case Excluded:
+		return "excluded"
+	case RequiresMissing:
+		return "requires missing"
+	case UnexpectedArg:
+		return "unexpected argument"
+	case DeprecatedUse:
+		return "deprecated"
+	case InvalidConfig:
+		return "invalid config"
+	default:
+		return fmt.Sprintf("ParseErrorKind(%d)", int(k))
+	}
+}
+
+// ParseError is a user error found while parsing. Its message is the same as
+// the plain errors the parser used to return; the fields describe it for code.
+type ParseError struct {
+	Kind     ParseErrorKind
+	Flag     string // name of the flag concerned, if any
+	Value    string // offending value, if any
+	ArgIndex int    // index of the offending argument in the root command's args, or -1
+	Cmd      string // name of the command being parsed
+
+	err error
+}
+
+func (e *ParseError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying cause, such as a validator's error.
+func (e *ParseError) Unwrap() error {
+	return errors.Unwrap(e.err)
+}
+
+// newParseError creates a ParseError whose message is formatted like fmt.Errorf,
+// including %w. ArgIndex and Cmd are filled in later by located.
+func newParseError(kind ParseErrorKind, flag, value string, format string, args ...any) *ParseError {
+	return &ParseError{Kind: kind, Flag: flag, Value: value, ArgIndex: -1, err: fmt.Errorf(format, args...)}
+}
+
+// errNotAFlag is returned by parseFlag for dash-prefixed arguments that are
+// negative numbers, to be treated as positional values.
+var errNotAFlag = errors.New("not a flag")
+
+// located fills in where a ParseError, or each of a ParseErrors, came from: the
+// index of the argument at fault (if index >= 0) and the command. Details
+// already set, e.g. by a subcommand, are kept.
+func (c *Cmd) located(err error, index int) error {
+	if errs, ok := err.(ParseErrors); ok {
+		for _, err := range errs {
+			c.located(err, index)
+		}
+		return err
+	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+	if parseErr.ArgIndex < 0 && index >= 0 {
+		parseErr.ArgIndex = index
+	}
+	if parseErr.Cmd == "" {
+		parseErr.Cmd = c.name
+	}
+	return err
+}
+
+// ParseErrors is returned by a parse with WithCollectErrors(true), listing every
+// problem found rather than only the first. Each error is on its own line.
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap lets errors.Is and errors.As match any of the collected errors.
+func (e ParseErrors) Unwrap() []error {
+	return e
+}
+
+// report returns err unchanged, unless errors are being collected, in which case
+// err is recorded and nil is returned so parsing carries on. Only user errors
+// are collected: programming errors and help, dump and completion requests still
+// end the parse. A subcommand's ParseErrors are merged in.
+func (c *parseCfg) report(err error) error {
+	if err == nil || !c.collectErrors {
+		return err
+	}
+
+	switch e := err.(type) {
+	case *ProgrammingError, *helpInvokedError, *dumpInvokedError, *completionInvokedError:
+		return err
+	case ParseErrors:
+		for _, sub := range e {
+			c.addError(sub)
+		}
+	default:
+		c.addError(err)
+	}
+	return nil
+}
+
+// addError records err unless an identical message was already recorded, as
+// when two flags exclude each other.
+func (c *parseCfg) addError(err error) {
+	if slices.ContainsFunc(c.errs, func(seen error) bool { return seen.Error() == err.Error() }) {
+		return
+	}
+	c.errs = append(c.errs, err)
+}
+
+// abort ends the parse on err, returning it along with any errors collected so far.
+func (c *parseCfg) abort(err error) error {
+	if err := c.report(err); err != nil {
+		return err
+	}
+	return c.collected()
+}
+
+// collected returns the errors collected during the parse, or nil if there were none.
+func (c *parseCfg) collected() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
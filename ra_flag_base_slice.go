@@ -8,6 +8,9 @@ type SliceFlag[T any] struct {
 	Variadic  bool
 	Default   *[]T
 	Value     *[]T
+	Validator func(T) error // run on each element
+
+	allowPrefixes *bool // integer slices only; nil uses the command's default
 }
 
 type StringSliceFlag = SliceFlag[string]
@@ -40,6 +43,21 @@ func (f *SliceFlag[T]) SetShort(s string) *SliceFlag[T] {
 	return f
 }
 
+func (f *SliceFlag[T]) SetAliases(aliases ...string) *SliceFlag[T] {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *SliceFlag[T]) SetDeprecated(msg string) *SliceFlag[T] {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *SliceFlag[T]) SetDeprecatedAlias(alias, msg string) *SliceFlag[T] {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
 func (f *SliceFlag[T]) SetUsage(u string) *SliceFlag[T] {
 	f.Usage = u
 	return f
@@ -85,6 +103,35 @@ func (f *SliceFlag[T]) SetRequires(flags []string) *SliceFlag[T] {
 	return f
 }
 
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *SliceFlag[T]) SetRequiredIf(flag, value string) *SliceFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *SliceFlag[T]) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *SliceFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *SliceFlag[T]) SetExcludesIf(flags []string, flag, value string) *SliceFlag[T] {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each element after the built-in constraints,
+// including the default elements at registration.
+func (f *SliceFlag[T]) SetValidator(fn func(v T) error) *SliceFlag[T] {
+	f.Validator = fn
+	return f
+}
+
 func (f *SliceFlag[T]) SetSeparator(sep string) *SliceFlag[T] {
 	f.Separator = &sep
 	return f
@@ -95,16 +142,34 @@ func (f *SliceFlag[T]) SetVariadic(b bool) *SliceFlag[T] {
 	return f
 }
 
+// SetAllowPrefixes accepts Go integer literal syntax for each element, as for
+// IntFlag.SetAllowPrefixes. Registering a slice of another element type with it
+// set is a ProgrammingError.
+func (f *SliceFlag[T]) SetAllowPrefixes(allow bool) *SliceFlag[T] {
+	f.allowPrefixes = &allow
+	return f
+}
+
 func (f *SliceFlag[T]) SetCustomUsageType(customType string) *SliceFlag[T] {
 	f.CustomUsageType = customType
 	return f
 }
 
+func (f *SliceFlag[T]) SetEnv(name string) *SliceFlag[T] {
+	f.Env = name
+	return f
+}
+
 func (f *SliceFlag[T]) SetCompletionFunc(fn CompletionFunc) *SliceFlag[T] {
 	f.CompletionFunc = fn
 	return f
 }
 
+func (f *SliceFlag[T]) SetContextCompletionFunc(fn ContextCompletionFunc) *SliceFlag[T] {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
 func (f *SliceFlag[T]) Register(cmd *Cmd, opts ...RegisterOption) (*[]T, error) {
 	ptr := new([]T)
 	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
@@ -116,6 +181,25 @@ func (f *SliceFlag[T]) RegisterWithPtr(cmd *Cmd, ptr *[]T, opts ...RegisterOptio
 		opt(regConf)
 	}
 
+	switch any(ptr).(type) {
+	case *[]int, *[]int64:
+	default:
+		if err := checkAllowPrefixes(f.Name, f.allowPrefixes, fmt.Sprintf("%T", *ptr)); err != nil {
+			return err
+		}
+	}
+
+	// Validate default value with the custom validator
+	if f.Default != nil {
+		if err := validateDefaults(f.Name, f.Validator, *f.Default...); err != nil {
+			return err
+		}
+	}
+
+	if err := cmd.validateFlagAliases(f.Name, f.Aliases); err != nil {
+		return err
+	}
+
 	if _, err := cmd.checkForGlobalFlagOverride(f.Name, f.Short, regConf.global); err != nil {
 		return err
 	}
@@ -4,6 +4,7 @@ import "fmt"
 
 type BoolFlag struct {
 	Flag[bool]
+	negatable *bool // whether --no-<name> sets the flag to false; nil uses the command's default
 }
 
 func NewBool(name string) *BoolFlag {
@@ -15,6 +16,21 @@ func (f *BoolFlag) SetShort(s string) *BoolFlag {
 	return f
 }
 
+func (f *BoolFlag) SetAliases(aliases ...string) *BoolFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *BoolFlag) SetDeprecated(msg string) *BoolFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *BoolFlag) SetDeprecatedAlias(alias, msg string) *BoolFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
 func (f *BoolFlag) SetUsage(u string) *BoolFlag {
 	f.Usage = u
 	return f
@@ -60,11 +76,52 @@ func (f *BoolFlag) SetRequires(flags []string) *BoolFlag {
 	return f
 }
 
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *BoolFlag) SetRequiredIf(flag, value string) *BoolFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *BoolFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *BoolFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *BoolFlag) SetExcludesIf(flags []string, flag, value string) *BoolFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *BoolFlag) SetValidator(fn func(v bool) error) *BoolFlag {
+	f.Validator = fn
+	return f
+}
+
+// SetNegatable registers --no-<name>, which sets the flag to false, overriding
+// the command's SetNegatableBools default.
+func (f *BoolFlag) SetNegatable(b bool) *BoolFlag {
+	f.negatable = &b
+	return f
+}
+
 func (f *BoolFlag) SetCustomUsageType(customType string) *BoolFlag {
 	f.CustomUsageType = customType
 	return f
 }
 
+func (f *BoolFlag) SetEnv(name string) *BoolFlag {
+	f.Env = name
+	return f
+}
+
 func (f *BoolFlag) Register(cmd *Cmd, opts ...RegisterOption) (*bool, error) {
 	ptr := new(bool)
 	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
@@ -86,6 +143,17 @@ func (f *BoolFlag) RegisterWithPtr(cmd *Cmd, ptr *bool, opts ...RegisterOption)
 		return fmt.Errorf("flag %q cannot be both PositionalOnly and FlagOnly (mutually exclusive)", f.Name)
 	}
 
+	// Validate default value with the custom validator
+	if f.Default != nil {
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	if err := cmd.validateFlagAliases(f.Name, f.Aliases); err != nil {
+		return err
+	}
+
 	if _, err := cmd.checkForGlobalFlagOverride(f.Name, f.Short, regConf.global); err != nil {
 		return err
 	}
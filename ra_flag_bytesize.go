@@ -0,0 +1,365 @@
+package ra
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Byte size units, for ByteSizeFlag defaults and min/max.
+const (
+	KB int64 = 1000
+	MB       = 1000 * KB
+	GB       = 1000 * MB
+	TB       = 1000 * GB
+	PB       = 1000 * TB
+
+	KiB int64 = 1024
+	MiB       = 1024 * KiB
+	GiB       = 1024 * MiB
+	TiB       = 1024 * GiB
+	PiB       = 1024 * TiB
+)
+
+// byteSizeUnits maps unit suffixes (lowercased) to their size. As in GNU tools, a
+// bare letter is binary (K = KiB) and a trailing B without i is decimal (KB = 1000).
+var byteSizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": KiB, "kib": KiB, "kb": KB,
+	"m": MiB, "mib": MiB, "mb": MB,
+	"g": GiB, "gib": GiB, "gb": GB,
+	"t": TiB, "tib": TiB, "tb": TB,
+	"p": PiB, "pib": PiB, "pb": PB,
+}
+
+// byteSizeFormatUnits are the units used to render sizes, largest first.
+var byteSizeFormatUnits = []struct {
+	name string
+	size int64
+}{
+	{"PiB", PiB}, {"PB", PB},
+	{"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB},
+	{"MiB", MiB}, {"MB", MB},
+	{"KiB", KiB}, {"KB", KB},
+}
+
+// ByteSizeFlag is an int64 flag holding a number of bytes, given in human-readable
+// form such as "512K", "1.5GiB" or "10MB".
+type ByteSizeFlag struct {
+	Flag[int64]
+	bounds bounds[int64]
+}
+
+func NewByteSize(name string) *ByteSizeFlag {
+	return &ByteSizeFlag{
+		Flag:   Flag[int64]{BaseFlag: BaseFlag{Name: name, Optional: false}},
+		bounds: bounds[int64]{compare: cmp.Compare[int64]},
+	}
+}
+
+// parseByteSize parses a non-negative size with an optional fractional part and a
+// case-insensitive unit, e.g. "512", "512K", "1.5GiB", "10MB". Fractional bytes
+// are truncated.
+func parseByteSize(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	i := 0
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+	numStr, unitStr := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	unit, ok := byteSizeUnits[unitStr]
+	if numStr == "" || !ok {
+		return 0, strconv.ErrSyntax
+	}
+
+	if n, err := strconv.ParseInt(numStr, 10, 64); err == nil {
+		if n > math.MaxInt64/unit {
+			return 0, strconv.ErrRange
+		}
+		return n * unit, nil
+	}
+
+	n, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return 0, strconv.ErrSyntax
+	}
+	bytes := n * float64(unit)
+	if bytes >= math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+	return int64(bytes), nil
+}
+
+// formatByteSize renders bytes in the largest unit that represents it exactly with
+// at most two decimals, e.g. "512KiB", "1.5GiB" or "10MB", falling back to a
+// rounded binary unit.
+func formatByteSize(bytes int64) string {
+	if bytes == 0 {
+		return "0B"
+	}
+	for _, u := range byteSizeFormatUnits {
+		if bytes >= u.size && (bytes%u.size)*100%u.size == 0 {
+			return formatByteSizeIn(bytes, u.size) + u.name
+		}
+	}
+	for _, u := range byteSizeFormatUnits {
+		if strings.HasSuffix(u.name, "iB") && bytes >= u.size {
+			return formatByteSizeIn(bytes, u.size) + u.name
+		}
+	}
+	return fmt.Sprintf("%dB", bytes)
+}
+
+func formatByteSizeIn(bytes, unit int64) string {
+	return strconv.FormatFloat(math.Round(float64(bytes)/float64(unit)*100)/100, 'f', -1, 64)
+}
+
+func (f *ByteSizeFlag) SetShort(s string) *ByteSizeFlag {
+	f.Short = s
+	return f
+}
+
+func (f *ByteSizeFlag) SetAliases(aliases ...string) *ByteSizeFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *ByteSizeFlag) SetDeprecated(msg string) *ByteSizeFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *ByteSizeFlag) SetDeprecatedAlias(alias, msg string) *ByteSizeFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *ByteSizeFlag) SetUsage(u string) *ByteSizeFlag {
+	f.Usage = u
+	return f
+}
+
+func (f *ByteSizeFlag) SetDefault(v int64) *ByteSizeFlag {
+	f.Default = &v
+	return f
+}
+
+func (f *ByteSizeFlag) SetOptional(b bool) *ByteSizeFlag {
+	f.Optional = b
+	return f
+}
+
+func (f *ByteSizeFlag) SetHidden(b bool) *ByteSizeFlag {
+	f.Hidden = b
+	return f
+}
+
+func (f *ByteSizeFlag) SetHiddenInShortHelp(b bool) *ByteSizeFlag {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *ByteSizeFlag) SetPositionalOnly(b bool) *ByteSizeFlag {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *ByteSizeFlag) SetFlagOnly(b bool) *ByteSizeFlag {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *ByteSizeFlag) SetExcludes(flags []string) *ByteSizeFlag {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *ByteSizeFlag) SetRequires(flags []string) *ByteSizeFlag {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *ByteSizeFlag) SetRequiredIf(flag, value string) *ByteSizeFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *ByteSizeFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *ByteSizeFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *ByteSizeFlag) SetExcludesIf(flags []string, flag, value string) *ByteSizeFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *ByteSizeFlag) SetValidator(fn func(v int64) error) *ByteSizeFlag {
+	f.Validator = fn
+	return f
+}
+
+// SetMin sets the minimum size in bytes, e.g. SetMin(4*ra.KiB, true).
+func (f *ByteSizeFlag) SetMin(min int64, inclusive bool) *ByteSizeFlag {
+	f.bounds.setMin(min, inclusive)
+	return f
+}
+
+// SetMax sets the maximum size in bytes, e.g. SetMax(10*ra.GiB, true).
+func (f *ByteSizeFlag) SetMax(max int64, inclusive bool) *ByteSizeFlag {
+	f.bounds.setMax(max, inclusive)
+	return f
+}
+
+func (f *ByteSizeFlag) SetCustomUsageType(customType string) *ByteSizeFlag {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *ByteSizeFlag) SetEnv(name string) *ByteSizeFlag {
+	f.Env = name
+	return f
+}
+
+func (f *ByteSizeFlag) SetCompletionFunc(fn CompletionFunc) *ByteSizeFlag {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *ByteSizeFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *ByteSizeFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *ByteSizeFlag) Register(cmd *Cmd, opts ...RegisterOption) (*int64, error) {
+	ptr := new(int64)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *ByteSizeFlag) RegisterWithPtr(cmd *Cmd, ptr *int64, opts ...RegisterOption) error {
+	// Validate default value against constraints
+	if f.Default != nil {
+		if err := f.bounds.check(*f.Default, formatByteSize); err != nil {
+			return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *ByteSizeFlag) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *ByteSizeFlag) set(value string) error {
+	bytes, err := parseByteSize(value)
+	if err == strconv.ErrRange {
+		return newParseError(InvalidValue, f.Name, value, "byte size overflow for %s: %s (value exceeds int64 range)", f.Name, value)
+	} else if err != nil {
+		return newParseError(InvalidValue, f.Name, value, "invalid byte size value for %s: %s (expected e.g. 512K, 1.5GiB, 10MB)", f.Name, value)
+	}
+	if err := f.bounds.check(bytes, formatByteSize); err != nil {
+		return newParseError(ConstraintViolation, f.Name, value, "'%s' %w", f.Name, err)
+	}
+	if err := validateValue(f.Name, f.Validator, bytes, value); err != nil {
+		return err
+	}
+	*f.Value = bytes
+	return nil
+}
+
+func (f *ByteSizeFlag) applyDefault() {
+	if f.Default != nil {
+		*f.Value = *f.Default
+	}
+}
+
+func (f *ByteSizeFlag) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *ByteSizeFlag) isSlice() bool {
+	return false
+}
+
+func (f *ByteSizeFlag) isVariadic() bool {
+	return false
+}
+
+func (f *ByteSizeFlag) separator() *string {
+	return nil
+}
+
+func (f *ByteSizeFlag) usageType() string {
+	return "size"
+}
+
+func (f *ByteSizeFlag) dumpType() string {
+	return "bytesize" + f.bounds.dumpString(formatByteSize)
+}
+
+func (f *ByteSizeFlag) defaultString() string {
+	if f.Default != nil {
+		return formatByteSize(*f.Default)
+	}
+	return ""
+}
+
+func (f *ByteSizeFlag) dumpDefault() string {
+	if f.Default != nil {
+		return formatByteSize(*f.Default)
+	}
+	return "none"
+}
+
+func (f *ByteSizeFlag) dumpCurrent() string {
+	if f.Value != nil && *f.Value != 0 {
+		return formatByteSize(*f.Value)
+	}
+	return ""
+}
+
+func (f *ByteSizeFlag) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return []string{formatByteSize(*f.Value)}
+}
+
+func (f *ByteSizeFlag) rangeString() string {
+	return f.bounds.rangeString(formatByteSize)
+}
+
+func (f *ByteSizeFlag) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *ByteSizeFlag) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *ByteSizeFlag) valuePtr() any {
+	return f.Value
+}
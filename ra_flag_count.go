@@ -0,0 +1,280 @@
+package ra
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+)
+
+// CountFlag is an int flag counting its occurrences, e.g. -v, -vvv or
+// --verbose --verbose. An explicit value (--verbose=3) sets the count directly.
+// Count flags take no value from the next argument and are always flag-only.
+type CountFlag struct {
+	Flag[int]
+	bounds          bounds[int]
+	defaultsInPlace bool // true until the first occurrence replaces the default
+}
+
+func NewCount(name string) *CountFlag {
+	return &CountFlag{
+		Flag:   Flag[int]{BaseFlag: BaseFlag{Name: name, Optional: false}},
+		bounds: bounds[int]{compare: cmp.Compare[int]},
+	}
+}
+
+func (f *CountFlag) SetShort(s string) *CountFlag {
+	f.Short = s
+	return f
+}
+
+func (f *CountFlag) SetAliases(aliases ...string) *CountFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *CountFlag) SetDeprecated(msg string) *CountFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *CountFlag) SetDeprecatedAlias(alias, msg string) *CountFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *CountFlag) SetUsage(u string) *CountFlag {
+	f.Usage = u
+	return f
+}
+
+// SetDefault sets the count used when the flag isn't given. Occurrences count
+// from zero rather than adding to the default.
+func (f *CountFlag) SetDefault(v int) *CountFlag {
+	f.Default = &v
+	return f
+}
+
+func (f *CountFlag) SetOptional(b bool) *CountFlag {
+	f.Optional = b
+	return f
+}
+
+func (f *CountFlag) SetHidden(b bool) *CountFlag {
+	f.Hidden = b
+	return f
+}
+
+func (f *CountFlag) SetHiddenInShortHelp(b bool) *CountFlag {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *CountFlag) SetExcludes(flags []string) *CountFlag {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *CountFlag) SetRequires(flags []string) *CountFlag {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *CountFlag) SetRequiredIf(flag, value string) *CountFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *CountFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *CountFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *CountFlag) SetExcludesIf(flags []string, flag, value string) *CountFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *CountFlag) SetValidator(fn func(v int) error) *CountFlag {
+	f.Validator = fn
+	return f
+}
+
+// SetMax sets the highest allowed count (inclusive); going past it is an error.
+func (f *CountFlag) SetMax(max int) *CountFlag {
+	f.bounds.setMax(max, true)
+	return f
+}
+
+func (f *CountFlag) SetCustomUsageType(customType string) *CountFlag {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *CountFlag) SetEnv(name string) *CountFlag {
+	f.Env = name
+	return f
+}
+
+func (f *CountFlag) SetCompletionFunc(fn CompletionFunc) *CountFlag {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *CountFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *CountFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *CountFlag) Register(cmd *Cmd, opts ...RegisterOption) (*int, error) {
+	ptr := new(int)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *CountFlag) RegisterWithPtr(cmd *Cmd, ptr *int, opts ...RegisterOption) error {
+	// Validate default value against constraints
+	if f.Default != nil {
+		if *f.Default < 0 {
+			return fmt.Errorf("invalid default value for flag %q: count cannot be negative", f.Name)
+		}
+		if err := f.bounds.check(*f.Default, strconv.Itoa); err != nil {
+			return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	// Count flags take no value, so can't be passed positionally
+	flag.FlagOnly = true
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+// increment records one occurrence of the flag.
+func (f *CountFlag) increment() error {
+	if f.defaultsInPlace {
+		*f.Value = 0
+		f.defaultsInPlace = false
+	}
+	if err := f.bounds.check(*f.Value+1, strconv.Itoa); err != nil {
+		return newParseError(ConstraintViolation, f.Name, strconv.Itoa(*f.Value+1), "'%s' %w", f.Name, err)
+	}
+	if err := validateValue(f.Name, f.Validator, *f.Value+1, strconv.Itoa(*f.Value+1)); err != nil {
+		return err
+	}
+	*f.Value++
+	return nil
+}
+
+func (f *CountFlag) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+// set assigns an explicit count, as given by --verbose=3, an env var or config.
+func (f *CountFlag) set(value string) error {
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return newParseError(InvalidValue, f.Name, value, "invalid count value for %s: %s (expected a non-negative integer)", f.Name, value)
+	}
+	if err := f.bounds.check(count, strconv.Itoa); err != nil {
+		return newParseError(ConstraintViolation, f.Name, value, "'%s' %w", f.Name, err)
+	}
+	if err := validateValue(f.Name, f.Validator, count, value); err != nil {
+		return err
+	}
+	*f.Value = count
+	f.defaultsInPlace = false
+	return nil
+}
+
+func (f *CountFlag) applyDefault() {
+	*f.Value = 0
+	if f.Default != nil {
+		*f.Value = *f.Default
+	}
+	f.defaultsInPlace = true
+}
+
+// hasDefault is true as count flags have an implicit default of 0.
+func (f *CountFlag) hasDefault() bool {
+	return true
+}
+
+func (f *CountFlag) isSlice() bool {
+	return false
+}
+
+func (f *CountFlag) isVariadic() bool {
+	return false
+}
+
+func (f *CountFlag) separator() *string {
+	return nil
+}
+
+// usageType is empty as, like bools, count flags show no type in help.
+func (f *CountFlag) usageType() string {
+	return ""
+}
+
+func (f *CountFlag) dumpType() string {
+	return "count" + f.bounds.dumpString(strconv.Itoa)
+}
+
+func (f *CountFlag) defaultString() string {
+	if f.Default != nil && *f.Default > 0 {
+		return strconv.Itoa(*f.Default)
+	}
+	return ""
+}
+
+func (f *CountFlag) dumpDefault() string {
+	if f.Default != nil {
+		return strconv.Itoa(*f.Default)
+	}
+	return "0"
+}
+
+func (f *CountFlag) dumpCurrent() string {
+	if f.Value != nil && *f.Value != 0 {
+		return strconv.Itoa(*f.Value)
+	}
+	return ""
+}
+
+func (f *CountFlag) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return []string{strconv.Itoa(*f.Value)}
+}
+
+func (f *CountFlag) rangeString() string {
+	return f.bounds.rangeString(strconv.Itoa)
+}
+
+func (f *CountFlag) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *CountFlag) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *CountFlag) valuePtr() any {
+	return f.Value
+}
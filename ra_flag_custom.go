@@ -0,0 +1,559 @@
+package ra
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CustomFlag is a flag of any type T, converted from and to strings by the
+// functions given to NewCustom.
+type CustomFlag[T any] struct {
+	Flag[T]
+	parse    func(string) (T, error)
+	format   func(T) string
+	typeName string
+}
+
+// CustomSliceFlag is the slice counterpart of CustomFlag.
+type CustomSliceFlag[T any] struct {
+	BaseFlag
+	Separator *string
+	Variadic  bool
+	Default   *[]T
+	Value     *[]T
+	Validator func(T) error // run on each element
+
+	parse           func(string) (T, error)
+	format          func(T) string
+	typeName        string
+	defaultsInPlace bool // true until the first user-provided value replaces the default
+}
+
+// NewCustom creates a flag of type T. parse converts command-line input, returning
+// an error for invalid values; format renders values for help and dump output
+// (fmt.Sprint if nil). The type shown in help is T's lowercased type name, e.g.
+// "url" for url.URL; override it with SetCustomUsageType.
+func NewCustom[T any](name string, parse func(string) (T, error), format func(T) string) *CustomFlag[T] {
+	return &CustomFlag[T]{
+		Flag:     Flag[T]{BaseFlag: BaseFlag{Name: name, Optional: false}},
+		parse:    parse,
+		format:   customFormatter(format),
+		typeName: customTypeName[T](),
+	}
+}
+
+// NewCustomSlice creates a slice flag of type T, with elements converted as for NewCustom.
+func NewCustomSlice[T any](name string, parse func(string) (T, error), format func(T) string) *CustomSliceFlag[T] {
+	return &CustomSliceFlag[T]{
+		BaseFlag: BaseFlag{Name: name, Optional: false},
+		parse:    parse,
+		format:   customFormatter(format),
+		typeName: customTypeName[T](),
+	}
+}
+
+func customFormatter[T any](format func(T) string) func(T) string {
+	if format != nil {
+		return format
+	}
+	return func(v T) string {
+		return fmt.Sprint(v)
+	}
+}
+
+func customTypeName[T any]() string {
+	name := reflect.TypeOf((*T)(nil)).Elem().Name()
+	if name == "" {
+		return "value"
+	}
+	return strings.ToLower(name)
+}
+
+func (f *CustomFlag[T]) SetShort(s string) *CustomFlag[T] {
+	f.Short = s
+	return f
+}
+
+func (f *CustomFlag[T]) SetAliases(aliases ...string) *CustomFlag[T] {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *CustomFlag[T]) SetDeprecated(msg string) *CustomFlag[T] {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *CustomFlag[T]) SetDeprecatedAlias(alias, msg string) *CustomFlag[T] {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *CustomFlag[T]) SetUsage(u string) *CustomFlag[T] {
+	f.Usage = u
+	return f
+}
+
+func (f *CustomFlag[T]) SetDefault(v T) *CustomFlag[T] {
+	f.Default = &v
+	return f
+}
+
+func (f *CustomFlag[T]) SetOptional(b bool) *CustomFlag[T] {
+	f.Optional = b
+	return f
+}
+
+func (f *CustomFlag[T]) SetHidden(b bool) *CustomFlag[T] {
+	f.Hidden = b
+	return f
+}
+
+func (f *CustomFlag[T]) SetHiddenInShortHelp(b bool) *CustomFlag[T] {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *CustomFlag[T]) SetPositionalOnly(b bool) *CustomFlag[T] {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *CustomFlag[T]) SetFlagOnly(b bool) *CustomFlag[T] {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *CustomFlag[T]) SetExcludes(flags []string) *CustomFlag[T] {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *CustomFlag[T]) SetRequires(flags []string) *CustomFlag[T] {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *CustomFlag[T]) SetRequiredIf(flag, value string) *CustomFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *CustomFlag[T]) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *CustomFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *CustomFlag[T]) SetExcludesIf(flags []string, flag, value string) *CustomFlag[T] {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *CustomFlag[T]) SetValidator(fn func(v T) error) *CustomFlag[T] {
+	f.Validator = fn
+	return f
+}
+
+func (f *CustomFlag[T]) SetCustomUsageType(customType string) *CustomFlag[T] {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *CustomFlag[T]) SetEnv(name string) *CustomFlag[T] {
+	f.Env = name
+	return f
+}
+
+func (f *CustomFlag[T]) SetCompletionFunc(fn CompletionFunc) *CustomFlag[T] {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *CustomFlag[T]) SetContextCompletionFunc(fn ContextCompletionFunc) *CustomFlag[T] {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *CustomFlag[T]) Register(cmd *Cmd, opts ...RegisterOption) (*T, error) {
+	ptr := new(T)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *CustomFlag[T]) RegisterWithPtr(cmd *Cmd, ptr *T, opts ...RegisterOption) error {
+	if f.parse == nil {
+		return fmt.Errorf("flag %q has no parse function", f.Name)
+	}
+
+	// Validate default value with the custom validator
+	if f.Default != nil {
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *CustomFlag[T]) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *CustomFlag[T]) set(value string) error {
+	val, err := f.parse(value)
+	if err != nil {
+		return newParseError(InvalidValue, f.Name, value, "invalid %s value for %s: %s (%v)", f.typeName, f.Name, value, err)
+	}
+	if err := validateValue(f.Name, f.Validator, val, value); err != nil {
+		return err
+	}
+	*f.Value = val
+	return nil
+}
+
+func (f *CustomFlag[T]) applyDefault() {
+	if f.Default != nil {
+		*f.Value = *f.Default
+	}
+}
+
+func (f *CustomFlag[T]) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *CustomFlag[T]) isSlice() bool {
+	return false
+}
+
+func (f *CustomFlag[T]) isVariadic() bool {
+	return false
+}
+
+func (f *CustomFlag[T]) separator() *string {
+	return nil
+}
+
+func (f *CustomFlag[T]) usageType() string {
+	return f.typeName
+}
+
+func (f *CustomFlag[T]) dumpType() string {
+	return fmt.Sprintf("%T", *new(T))
+}
+
+func (f *CustomFlag[T]) defaultString() string {
+	if f.Default != nil {
+		return f.format(*f.Default)
+	}
+	return ""
+}
+
+func (f *CustomFlag[T]) dumpDefault() string {
+	if f.Default != nil {
+		return f.format(*f.Default)
+	}
+	return "none"
+}
+
+func (f *CustomFlag[T]) dumpCurrent() string {
+	if f.Value == nil {
+		return ""
+	}
+	current := f.format(*f.Value)
+	if current == f.format(*new(T)) {
+		return ""
+	}
+	return current
+}
+
+func (f *CustomFlag[T]) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return []string{f.format(*f.Value)}
+}
+
+func (f *CustomFlag[T]) rangeString() string {
+	return ""
+}
+
+func (f *CustomFlag[T]) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *CustomFlag[T]) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *CustomFlag[T]) valuePtr() any {
+	return f.Value
+}
+
+func (f *CustomSliceFlag[T]) SetShort(s string) *CustomSliceFlag[T] {
+	f.Short = s
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetAliases(aliases ...string) *CustomSliceFlag[T] {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetDeprecated(msg string) *CustomSliceFlag[T] {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetDeprecatedAlias(alias, msg string) *CustomSliceFlag[T] {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetUsage(u string) *CustomSliceFlag[T] {
+	f.Usage = u
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetDefault(v []T) *CustomSliceFlag[T] {
+	f.Default = &v
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetOptional(b bool) *CustomSliceFlag[T] {
+	f.Optional = b
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetHidden(b bool) *CustomSliceFlag[T] {
+	f.Hidden = b
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetHiddenInShortHelp(b bool) *CustomSliceFlag[T] {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetPositionalOnly(b bool) *CustomSliceFlag[T] {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetFlagOnly(b bool) *CustomSliceFlag[T] {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetExcludes(flags []string) *CustomSliceFlag[T] {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetRequires(flags []string) *CustomSliceFlag[T] {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *CustomSliceFlag[T]) SetRequiredIf(flag, value string) *CustomSliceFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *CustomSliceFlag[T]) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *CustomSliceFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *CustomSliceFlag[T]) SetExcludesIf(flags []string, flag, value string) *CustomSliceFlag[T] {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each element after the built-in constraints,
+// including the default elements at registration.
+func (f *CustomSliceFlag[T]) SetValidator(fn func(v T) error) *CustomSliceFlag[T] {
+	f.Validator = fn
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetSeparator(sep string) *CustomSliceFlag[T] {
+	f.Separator = &sep
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetVariadic(b bool) *CustomSliceFlag[T] {
+	f.Variadic = b
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetCustomUsageType(customType string) *CustomSliceFlag[T] {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetEnv(name string) *CustomSliceFlag[T] {
+	f.Env = name
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetCompletionFunc(fn CompletionFunc) *CustomSliceFlag[T] {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *CustomSliceFlag[T]) SetContextCompletionFunc(fn ContextCompletionFunc) *CustomSliceFlag[T] {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *CustomSliceFlag[T]) Register(cmd *Cmd, opts ...RegisterOption) (*[]T, error) {
+	ptr := new([]T)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *CustomSliceFlag[T]) RegisterWithPtr(cmd *Cmd, ptr *[]T, opts ...RegisterOption) error {
+	if f.parse == nil {
+		return fmt.Errorf("flag %q has no parse function", f.Name)
+	}
+
+	// Validate default value with the custom validator
+	if f.Default != nil {
+		if err := validateDefaults(f.Name, f.Validator, *f.Default...); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *CustomSliceFlag[T]) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *CustomSliceFlag[T]) set(value string) error {
+	replace := f.defaultsInPlace
+	err := appendSliceValue(f.Value, value, f.Separator, replace, func(part string) (T, error) {
+		val, err := f.parse(part)
+		if err != nil {
+			return val, newParseError(InvalidValue, f.Name, part, "invalid %s value for %s: %s (%v)", f.typeName, f.Name, part, err)
+		}
+		return val, validateValue(f.Name, f.Validator, val, part)
+	})
+	if err == nil {
+		f.defaultsInPlace = false
+	}
+	return err
+}
+
+func (f *CustomSliceFlag[T]) applyDefault() {
+	if f.Default != nil {
+		*f.Value = append([]T{}, *f.Default...)
+	} else {
+		*f.Value = []T{}
+	}
+	f.defaultsInPlace = true
+}
+
+func (f *CustomSliceFlag[T]) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *CustomSliceFlag[T]) isSlice() bool {
+	return true
+}
+
+func (f *CustomSliceFlag[T]) isVariadic() bool {
+	return f.Variadic
+}
+
+func (f *CustomSliceFlag[T]) separator() *string {
+	return f.Separator
+}
+
+func (f *CustomSliceFlag[T]) usageType() string {
+	return sliceUsageType(f.typeName, f.Variadic)
+}
+
+func (f *CustomSliceFlag[T]) dumpType() string {
+	return sliceDumpType(fmt.Sprintf("%T", *new(T)), f.Variadic, f.Separator)
+}
+
+func (f *CustomSliceFlag[T]) formatAll(values []T) []string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, f.format(v))
+	}
+	return strs
+}
+
+func (f *CustomSliceFlag[T]) defaultString() string {
+	if f.Default != nil && len(*f.Default) > 0 {
+		return formatListDefault(f.formatAll(*f.Default))
+	}
+	return ""
+}
+
+func (f *CustomSliceFlag[T]) dumpDefault() string {
+	if f.Default != nil {
+		return fmt.Sprintf("%v", f.formatAll(*f.Default))
+	}
+	return "[]"
+}
+
+func (f *CustomSliceFlag[T]) dumpCurrent() string {
+	if f.Value != nil && len(*f.Value) > 0 {
+		return fmt.Sprintf("%v", f.formatAll(*f.Value))
+	}
+	return ""
+}
+
+func (f *CustomSliceFlag[T]) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return f.formatAll(*f.Value)
+}
+
+func (f *CustomSliceFlag[T]) rangeString() string {
+	return ""
+}
+
+func (f *CustomSliceFlag[T]) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *CustomSliceFlag[T]) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *CustomSliceFlag[T]) valuePtr() any {
+	return f.Value
+}
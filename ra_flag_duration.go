@@ -0,0 +1,569 @@
+package ra
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DurationFlag is a time.Duration flag, parsed with time.ParseDuration (e.g. "90s", "1h30m").
+type DurationFlag struct {
+	Flag[time.Duration]
+	bounds bounds[time.Duration]
+}
+
+// DurationSliceFlag is the slice counterpart of DurationFlag. Min/max apply to each element.
+type DurationSliceFlag struct {
+	BaseFlag
+	Separator *string
+	Variadic  bool
+	Default   *[]time.Duration
+	Value     *[]time.Duration
+	Validator func(time.Duration) error // run on each element
+
+	bounds          bounds[time.Duration]
+	defaultsInPlace bool // true until the first user-provided value replaces the default
+}
+
+func NewDuration(name string) *DurationFlag {
+	return &DurationFlag{
+		Flag:   Flag[time.Duration]{BaseFlag: BaseFlag{Name: name, Optional: false}},
+		bounds: bounds[time.Duration]{compare: cmp.Compare[time.Duration]},
+	}
+}
+
+func NewDurationSlice(name string) *DurationSliceFlag {
+	return &DurationSliceFlag{
+		BaseFlag: BaseFlag{Name: name, Optional: false},
+		bounds:   bounds[time.Duration]{compare: cmp.Compare[time.Duration]},
+	}
+}
+
+// formatDuration renders d like time.Duration.String, minus redundant zero units,
+// e.g. "1m" rather than "1m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+func parseDurationValue(name, value string, b *bounds[time.Duration]) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, newParseError(InvalidValue, name, value, "invalid duration value for %s: %s (expected e.g. 300ms, 1.5h, 2h45m)", name, value)
+	}
+	if err := b.check(d, formatDuration); err != nil {
+		return 0, newParseError(ConstraintViolation, name, value, "'%s' %w", name, err)
+	}
+	return d, nil
+}
+
+func (f *DurationFlag) SetShort(s string) *DurationFlag {
+	f.Short = s
+	return f
+}
+
+func (f *DurationFlag) SetAliases(aliases ...string) *DurationFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *DurationFlag) SetDeprecated(msg string) *DurationFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *DurationFlag) SetDeprecatedAlias(alias, msg string) *DurationFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *DurationFlag) SetUsage(u string) *DurationFlag {
+	f.Usage = u
+	return f
+}
+
+func (f *DurationFlag) SetDefault(v time.Duration) *DurationFlag {
+	f.Default = &v
+	return f
+}
+
+func (f *DurationFlag) SetOptional(b bool) *DurationFlag {
+	f.Optional = b
+	return f
+}
+
+func (f *DurationFlag) SetHidden(b bool) *DurationFlag {
+	f.Hidden = b
+	return f
+}
+
+func (f *DurationFlag) SetHiddenInShortHelp(b bool) *DurationFlag {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *DurationFlag) SetPositionalOnly(b bool) *DurationFlag {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *DurationFlag) SetFlagOnly(b bool) *DurationFlag {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *DurationFlag) SetExcludes(flags []string) *DurationFlag {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *DurationFlag) SetRequires(flags []string) *DurationFlag {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *DurationFlag) SetRequiredIf(flag, value string) *DurationFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *DurationFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *DurationFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *DurationFlag) SetExcludesIf(flags []string, flag, value string) *DurationFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *DurationFlag) SetValidator(fn func(v time.Duration) error) *DurationFlag {
+	f.Validator = fn
+	return f
+}
+
+func (f *DurationFlag) SetMin(min time.Duration, inclusive bool) *DurationFlag {
+	f.bounds.setMin(min, inclusive)
+	return f
+}
+
+func (f *DurationFlag) SetMax(max time.Duration, inclusive bool) *DurationFlag {
+	f.bounds.setMax(max, inclusive)
+	return f
+}
+
+func (f *DurationFlag) SetCustomUsageType(customType string) *DurationFlag {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *DurationFlag) SetEnv(name string) *DurationFlag {
+	f.Env = name
+	return f
+}
+
+func (f *DurationFlag) SetCompletionFunc(fn CompletionFunc) *DurationFlag {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *DurationFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *DurationFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *DurationFlag) Register(cmd *Cmd, opts ...RegisterOption) (*time.Duration, error) {
+	ptr := new(time.Duration)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *DurationFlag) RegisterWithPtr(cmd *Cmd, ptr *time.Duration, opts ...RegisterOption) error {
+	// Validate default value against constraints
+	if f.Default != nil {
+		if err := f.bounds.check(*f.Default, formatDuration); err != nil {
+			return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *DurationFlag) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *DurationFlag) set(value string) error {
+	d, err := parseDurationValue(f.Name, value, &f.bounds)
+	if err != nil {
+		return err
+	}
+	if err := validateValue(f.Name, f.Validator, d, value); err != nil {
+		return err
+	}
+	*f.Value = d
+	return nil
+}
+
+func (f *DurationFlag) applyDefault() {
+	if f.Default != nil {
+		*f.Value = *f.Default
+	}
+}
+
+func (f *DurationFlag) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *DurationFlag) isSlice() bool {
+	return false
+}
+
+func (f *DurationFlag) isVariadic() bool {
+	return false
+}
+
+func (f *DurationFlag) separator() *string {
+	return nil
+}
+
+func (f *DurationFlag) usageType() string {
+	return "duration"
+}
+
+func (f *DurationFlag) dumpType() string {
+	return "duration" + f.bounds.dumpString(formatDuration)
+}
+
+func (f *DurationFlag) defaultString() string {
+	if f.Default != nil {
+		return formatDuration(*f.Default)
+	}
+	return ""
+}
+
+func (f *DurationFlag) dumpDefault() string {
+	if f.Default != nil {
+		return formatDuration(*f.Default)
+	}
+	return "none"
+}
+
+func (f *DurationFlag) dumpCurrent() string {
+	if f.Value != nil && *f.Value != 0 {
+		return formatDuration(*f.Value)
+	}
+	return ""
+}
+
+func (f *DurationFlag) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return []string{formatDuration(*f.Value)}
+}
+
+func (f *DurationFlag) rangeString() string {
+	return f.bounds.rangeString(formatDuration)
+}
+
+func (f *DurationFlag) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *DurationFlag) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *DurationFlag) valuePtr() any {
+	return f.Value
+}
+
+func (f *DurationSliceFlag) SetShort(s string) *DurationSliceFlag {
+	f.Short = s
+	return f
+}
+
+func (f *DurationSliceFlag) SetAliases(aliases ...string) *DurationSliceFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *DurationSliceFlag) SetDeprecated(msg string) *DurationSliceFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *DurationSliceFlag) SetDeprecatedAlias(alias, msg string) *DurationSliceFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *DurationSliceFlag) SetUsage(u string) *DurationSliceFlag {
+	f.Usage = u
+	return f
+}
+
+func (f *DurationSliceFlag) SetDefault(v []time.Duration) *DurationSliceFlag {
+	f.Default = &v
+	return f
+}
+
+func (f *DurationSliceFlag) SetOptional(b bool) *DurationSliceFlag {
+	f.Optional = b
+	return f
+}
+
+func (f *DurationSliceFlag) SetHidden(b bool) *DurationSliceFlag {
+	f.Hidden = b
+	return f
+}
+
+func (f *DurationSliceFlag) SetHiddenInShortHelp(b bool) *DurationSliceFlag {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *DurationSliceFlag) SetPositionalOnly(b bool) *DurationSliceFlag {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *DurationSliceFlag) SetFlagOnly(b bool) *DurationSliceFlag {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *DurationSliceFlag) SetExcludes(flags []string) *DurationSliceFlag {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *DurationSliceFlag) SetRequires(flags []string) *DurationSliceFlag {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *DurationSliceFlag) SetRequiredIf(flag, value string) *DurationSliceFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *DurationSliceFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *DurationSliceFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *DurationSliceFlag) SetExcludesIf(flags []string, flag, value string) *DurationSliceFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each element after the built-in constraints,
+// including the default elements at registration.
+func (f *DurationSliceFlag) SetValidator(fn func(v time.Duration) error) *DurationSliceFlag {
+	f.Validator = fn
+	return f
+}
+
+func (f *DurationSliceFlag) SetSeparator(sep string) *DurationSliceFlag {
+	f.Separator = &sep
+	return f
+}
+
+func (f *DurationSliceFlag) SetVariadic(b bool) *DurationSliceFlag {
+	f.Variadic = b
+	return f
+}
+
+func (f *DurationSliceFlag) SetMin(min time.Duration, inclusive bool) *DurationSliceFlag {
+	f.bounds.setMin(min, inclusive)
+	return f
+}
+
+func (f *DurationSliceFlag) SetMax(max time.Duration, inclusive bool) *DurationSliceFlag {
+	f.bounds.setMax(max, inclusive)
+	return f
+}
+
+func (f *DurationSliceFlag) SetCustomUsageType(customType string) *DurationSliceFlag {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *DurationSliceFlag) SetEnv(name string) *DurationSliceFlag {
+	f.Env = name
+	return f
+}
+
+func (f *DurationSliceFlag) SetCompletionFunc(fn CompletionFunc) *DurationSliceFlag {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *DurationSliceFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *DurationSliceFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *DurationSliceFlag) Register(cmd *Cmd, opts ...RegisterOption) (*[]time.Duration, error) {
+	ptr := new([]time.Duration)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *DurationSliceFlag) RegisterWithPtr(cmd *Cmd, ptr *[]time.Duration, opts ...RegisterOption) error {
+	// Validate default values against constraints
+	if f.Default != nil {
+		for _, d := range *f.Default {
+			if err := f.bounds.check(d, formatDuration); err != nil {
+				return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+			}
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default...); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *DurationSliceFlag) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *DurationSliceFlag) set(value string) error {
+	replace := f.defaultsInPlace
+	err := appendSliceValue(f.Value, value, f.Separator, replace, func(part string) (time.Duration, error) {
+		d, err := parseDurationValue(f.Name, part, &f.bounds)
+		if err != nil {
+			return 0, err
+		}
+		return d, validateValue(f.Name, f.Validator, d, part)
+	})
+	if err == nil {
+		f.defaultsInPlace = false
+	}
+	return err
+}
+
+func (f *DurationSliceFlag) applyDefault() {
+	if f.Default != nil {
+		*f.Value = append([]time.Duration{}, *f.Default...)
+	} else {
+		*f.Value = []time.Duration{}
+	}
+	f.defaultsInPlace = true
+}
+
+func (f *DurationSliceFlag) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *DurationSliceFlag) isSlice() bool {
+	return true
+}
+
+func (f *DurationSliceFlag) isVariadic() bool {
+	return f.Variadic
+}
+
+func (f *DurationSliceFlag) separator() *string {
+	return f.Separator
+}
+
+func (f *DurationSliceFlag) usageType() string {
+	return sliceUsageType("duration", f.Variadic)
+}
+
+func (f *DurationSliceFlag) dumpType() string {
+	return sliceDumpType("duration"+f.bounds.dumpString(formatDuration), f.Variadic, f.Separator)
+}
+
+func formatDurations(values []time.Duration) []string {
+	strs := make([]string, 0, len(values))
+	for _, d := range values {
+		strs = append(strs, formatDuration(d))
+	}
+	return strs
+}
+
+func (f *DurationSliceFlag) defaultString() string {
+	if f.Default != nil && len(*f.Default) > 0 {
+		return formatListDefault(formatDurations(*f.Default))
+	}
+	return ""
+}
+
+func (f *DurationSliceFlag) dumpDefault() string {
+	if f.Default != nil {
+		return fmt.Sprintf("%v", formatDurations(*f.Default))
+	}
+	return "[]"
+}
+
+func (f *DurationSliceFlag) dumpCurrent() string {
+	if f.Value != nil && len(*f.Value) > 0 {
+		return fmt.Sprintf("%v", formatDurations(*f.Value))
+	}
+	return ""
+}
+
+func (f *DurationSliceFlag) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return formatDurations(*f.Value)
+}
+
+func (f *DurationSliceFlag) rangeString() string {
+	return f.bounds.rangeString(formatDuration)
+}
+
+func (f *DurationSliceFlag) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *DurationSliceFlag) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *DurationSliceFlag) valuePtr() any {
+	return f.Value
+}
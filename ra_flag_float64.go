@@ -18,6 +18,21 @@ func (f *Float64Flag) SetShort(s string) *Float64Flag {
 	return f
 }
 
+func (f *Float64Flag) SetAliases(aliases ...string) *Float64Flag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *Float64Flag) SetDeprecated(msg string) *Float64Flag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *Float64Flag) SetDeprecatedAlias(alias, msg string) *Float64Flag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
 func (f *Float64Flag) SetUsage(u string) *Float64Flag {
 	f.Usage = u
 	return f
@@ -63,6 +78,35 @@ func (f *Float64Flag) SetRequires(flags []string) *Float64Flag {
 	return f
 }
 
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *Float64Flag) SetRequiredIf(flag, value string) *Float64Flag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *Float64Flag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *Float64Flag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *Float64Flag) SetExcludesIf(flags []string, flag, value string) *Float64Flag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *Float64Flag) SetValidator(fn func(v float64) error) *Float64Flag {
+	f.Validator = fn
+	return f
+}
+
 func (f *Float64Flag) SetMin(min float64, inclusive bool) *Float64Flag {
 	f.min = &min
 	f.minInclusive = &inclusive
@@ -80,11 +124,21 @@ func (f *Float64Flag) SetCustomUsageType(customType string) *Float64Flag {
 	return f
 }
 
+func (f *Float64Flag) SetEnv(name string) *Float64Flag {
+	f.Env = name
+	return f
+}
+
 func (f *Float64Flag) SetCompletionFunc(fn CompletionFunc) *Float64Flag {
 	f.CompletionFunc = fn
 	return f
 }
 
+func (f *Float64Flag) SetContextCompletionFunc(fn ContextCompletionFunc) *Float64Flag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
 func (f *Float64Flag) Register(cmd *Cmd, opts ...RegisterOption) (*float64, error) {
 	ptr := new(float64)
 	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
@@ -96,6 +150,17 @@ func (f *Float64Flag) RegisterWithPtr(cmd *Cmd, ptr *float64, opts ...RegisterOp
 		opt(regConf)
 	}
 
+	// Validate default value with the custom validator
+	if f.Default != nil {
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	if err := cmd.validateFlagAliases(f.Name, f.Aliases); err != nil {
+		return err
+	}
+
 	if _, err := cmd.checkForGlobalFlagOverride(f.Name, f.Short, regConf.global); err != nil {
 		return err
 	}
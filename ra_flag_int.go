@@ -8,6 +8,9 @@ type IntFlag struct {
 	max          *int
 	minInclusive *bool
 	maxInclusive *bool
+
+	allowPrefixes *bool // nil uses the command's default (see SetAllowPrefixes)
+	noOptDefault  *int  // value when given without one, e.g. --level; nil requires a value
 }
 
 func NewInt(name string) *IntFlag {
@@ -19,6 +22,21 @@ func (f *IntFlag) SetShort(s string) *IntFlag {
 	return f
 }
 
+func (f *IntFlag) SetAliases(aliases ...string) *IntFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *IntFlag) SetDeprecated(msg string) *IntFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *IntFlag) SetDeprecatedAlias(alias, msg string) *IntFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
 func (f *IntFlag) SetUsage(u string) *IntFlag {
 	f.Usage = u
 	return f
@@ -64,6 +82,35 @@ func (f *IntFlag) SetRequires(flags []string) *IntFlag {
 	return f
 }
 
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *IntFlag) SetRequiredIf(flag, value string) *IntFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *IntFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *IntFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *IntFlag) SetExcludesIf(flags []string, flag, value string) *IntFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *IntFlag) SetValidator(fn func(v int) error) *IntFlag {
+	f.Validator = fn
+	return f
+}
+
 func (f *IntFlag) SetMin(min int, inclusive bool) *IntFlag {
 	f.min = &min
 	f.minInclusive = &inclusive
@@ -76,16 +123,41 @@ func (f *IntFlag) SetMax(max int, inclusive bool) *IntFlag {
 	return f
 }
 
+// SetAllowPrefixes accepts Go integer literal syntax in addition to decimal:
+// 0x1F, 0o755 (or 0755), 0b1010 and underscores like 1_000_000. Defaults to the
+// command's SetAllowIntPrefixes.
+func (f *IntFlag) SetAllowPrefixes(allow bool) *IntFlag {
+	f.allowPrefixes = &allow
+	return f
+}
+
+// SetNoOptDefault makes the flag's value optional: given without one (--color
+// rather than --color=never) it takes v, and it never consumes the next argument.
+func (f *IntFlag) SetNoOptDefault(v int) *IntFlag {
+	f.noOptDefault = &v
+	return f
+}
+
 func (f *IntFlag) SetCustomUsageType(customType string) *IntFlag {
 	f.CustomUsageType = customType
 	return f
 }
 
+func (f *IntFlag) SetEnv(name string) *IntFlag {
+	f.Env = name
+	return f
+}
+
 func (f *IntFlag) SetCompletionFunc(fn CompletionFunc) *IntFlag {
 	f.CompletionFunc = fn
 	return f
 }
 
+func (f *IntFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *IntFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
 func (f *IntFlag) Register(cmd *Cmd, opts ...RegisterOption) (*int, error) {
 	ptr := new(int)
 	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
@@ -114,6 +186,17 @@ func (f *IntFlag) RegisterWithPtr(cmd *Cmd, ptr *int, opts ...RegisterOption) er
 		}
 	}
 
+	// Validate no-opt default against constraints
+	if f.noOptDefault != nil {
+		if err := f.validateDefaultValue(*f.noOptDefault); err != nil {
+			return fmt.Errorf("invalid no-opt default value for flag %q: %w", f.Name, err)
+		}
+	}
+
+	if err := cmd.validateFlagAliases(f.Name, f.Aliases); err != nil {
+		return err
+	}
+
 	if _, err := cmd.checkForGlobalFlagOverride(f.Name, f.Short, regConf.global); err != nil {
 		return err
 	}
@@ -182,5 +265,11 @@ func (f *IntFlag) validateDefaultValue(value int) error {
 		}
 	}
 
+	// Check custom validator
+	if f.Validator != nil {
+		if err := f.Validator(value); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -8,6 +8,8 @@ type Int64Flag struct {
 	max          *int64
 	minInclusive *bool
 	maxInclusive *bool
+
+	allowPrefixes *bool // nil uses the command's default (see SetAllowPrefixes)
 }
 
 func NewInt64(name string) *Int64Flag {
@@ -19,6 +21,21 @@ func (f *Int64Flag) SetShort(s string) *Int64Flag {
 	return f
 }
 
+func (f *Int64Flag) SetAliases(aliases ...string) *Int64Flag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *Int64Flag) SetDeprecated(msg string) *Int64Flag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *Int64Flag) SetDeprecatedAlias(alias, msg string) *Int64Flag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
 func (f *Int64Flag) SetUsage(u string) *Int64Flag {
 	f.Usage = u
 	return f
@@ -64,6 +81,35 @@ func (f *Int64Flag) SetRequires(flags []string) *Int64Flag {
 	return f
 }
 
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *Int64Flag) SetRequiredIf(flag, value string) *Int64Flag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *Int64Flag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *Int64Flag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *Int64Flag) SetExcludesIf(flags []string, flag, value string) *Int64Flag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *Int64Flag) SetValidator(fn func(v int64) error) *Int64Flag {
+	f.Validator = fn
+	return f
+}
+
 func (f *Int64Flag) SetMin(min int64, inclusive bool) *Int64Flag {
 	f.min = &min
 	f.minInclusive = &inclusive
@@ -76,16 +122,34 @@ func (f *Int64Flag) SetMax(max int64, inclusive bool) *Int64Flag {
 	return f
 }
 
+// SetAllowPrefixes accepts Go integer literal syntax in addition to decimal:
+// 0x1F, 0o755 (or 0755), 0b1010 and underscores like 1_000_000. Defaults to the
+// command's SetAllowIntPrefixes.
+func (f *Int64Flag) SetAllowPrefixes(allow bool) *Int64Flag {
+	f.allowPrefixes = &allow
+	return f
+}
+
 func (f *Int64Flag) SetCustomUsageType(customType string) *Int64Flag {
 	f.CustomUsageType = customType
 	return f
 }
 
+func (f *Int64Flag) SetEnv(name string) *Int64Flag {
+	f.Env = name
+	return f
+}
+
 func (f *Int64Flag) SetCompletionFunc(fn CompletionFunc) *Int64Flag {
 	f.CompletionFunc = fn
 	return f
 }
 
+func (f *Int64Flag) SetContextCompletionFunc(fn ContextCompletionFunc) *Int64Flag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
 func (f *Int64Flag) Register(cmd *Cmd, opts ...RegisterOption) (*int64, error) {
 	ptr := new(int64)
 	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
@@ -97,6 +161,17 @@ func (f *Int64Flag) RegisterWithPtr(cmd *Cmd, ptr *int64, opts ...RegisterOption
 		opt(regConf)
 	}
 
+	// Validate default value with the custom validator
+	if f.Default != nil {
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	if err := cmd.validateFlagAliases(f.Name, f.Aliases); err != nil {
+		return err
+	}
+
 	if _, err := cmd.checkForGlobalFlagOverride(f.Name, f.Short, regConf.global); err != nil {
 		return err
 	}
@@ -0,0 +1,401 @@
+package ra
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DuplicateKeyPolicy controls what a map flag does when a key is given twice.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysLastWins keeps the last value given for a key.
+	DuplicateKeysLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeysError rejects a key given more than once.
+	DuplicateKeysError
+)
+
+// MapFlag is a key=value flag collecting entries into a map, from repeated
+// occurrences (--label env=prod --label team=core) and/or separator-joined ones
+// (--label env=prod,team=core).
+type MapFlag[V any] struct {
+	BaseFlag
+	Separator *string // splits entries within one value; "," unless changed
+	Default   *map[string]V
+	Value     *map[string]V
+	Validator func(V) error // run on each value
+
+	KeyEnumConstraint  *[]string      // allowed keys
+	KeyRegexConstraint *regexp.Regexp // pattern keys must match
+	DuplicateKeys      DuplicateKeyPolicy
+
+	parse           func(string) (V, error)
+	format          func(V) string
+	valueType       string // value type shown in help, e.g. "str"
+	valueDumpType   string // value type shown in dump output, e.g. "string"
+	defaultsInPlace bool   // true until the first user-provided value replaces the default
+}
+
+type StringMapFlag = MapFlag[string]
+type IntMapFlag = MapFlag[int]
+
+func NewStringMap(name string) *StringMapFlag {
+	sep := ","
+	return &MapFlag[string]{
+		BaseFlag:      BaseFlag{Name: name, Optional: false},
+		Separator:     &sep,
+		parse:         func(s string) (string, error) { return s, nil },
+		format:        func(s string) string { return s },
+		valueType:     "str",
+		valueDumpType: "string",
+	}
+}
+
+func NewIntMap(name string) *IntMapFlag {
+	sep := ","
+	return &MapFlag[int]{
+		BaseFlag:      BaseFlag{Name: name, Optional: false},
+		Separator:     &sep,
+		parse:         strconv.Atoi,
+		format:        strconv.Itoa,
+		valueType:     "int",
+		valueDumpType: "int",
+	}
+}
+
+func (f *MapFlag[V]) SetShort(s string) *MapFlag[V] {
+	f.Short = s
+	return f
+}
+
+func (f *MapFlag[V]) SetAliases(aliases ...string) *MapFlag[V] {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *MapFlag[V]) SetDeprecated(msg string) *MapFlag[V] {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *MapFlag[V]) SetDeprecatedAlias(alias, msg string) *MapFlag[V] {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *MapFlag[V]) SetUsage(u string) *MapFlag[V] {
+	f.Usage = u
+	return f
+}
+
+func (f *MapFlag[V]) SetDefault(v map[string]V) *MapFlag[V] {
+	f.Default = &v
+	return f
+}
+
+func (f *MapFlag[V]) SetOptional(b bool) *MapFlag[V] {
+	f.Optional = b
+	return f
+}
+
+func (f *MapFlag[V]) SetHidden(b bool) *MapFlag[V] {
+	f.Hidden = b
+	return f
+}
+
+func (f *MapFlag[V]) SetHiddenInShortHelp(b bool) *MapFlag[V] {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *MapFlag[V]) SetPositionalOnly(b bool) *MapFlag[V] {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *MapFlag[V]) SetFlagOnly(b bool) *MapFlag[V] {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *MapFlag[V]) SetExcludes(flags []string) *MapFlag[V] {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *MapFlag[V]) SetRequires(flags []string) *MapFlag[V] {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *MapFlag[V]) SetRequiredIf(flag, value string) *MapFlag[V] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *MapFlag[V]) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *MapFlag[V] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *MapFlag[V]) SetExcludesIf(flags []string, flag, value string) *MapFlag[V] {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each map value after parsing, including the
+// default values at registration. Keys are checked by the key constraints.
+func (f *MapFlag[V]) SetValidator(fn func(v V) error) *MapFlag[V] {
+	f.Validator = fn
+	return f
+}
+
+// SetSeparator sets the separator between entries within one value. An empty
+// separator disables splitting, so each value is a single entry.
+func (f *MapFlag[V]) SetSeparator(sep string) *MapFlag[V] {
+	if sep == "" {
+		f.Separator = nil
+	} else {
+		f.Separator = &sep
+	}
+	return f
+}
+
+func (f *MapFlag[V]) SetKeyEnumConstraint(keys []string) *MapFlag[V] {
+	f.KeyEnumConstraint = &keys
+	return f
+}
+
+func (f *MapFlag[V]) SetKeyRegexConstraint(regex *regexp.Regexp) *MapFlag[V] {
+	f.KeyRegexConstraint = regex
+	return f
+}
+
+func (f *MapFlag[V]) SetDuplicateKeys(policy DuplicateKeyPolicy) *MapFlag[V] {
+	f.DuplicateKeys = policy
+	return f
+}
+
+func (f *MapFlag[V]) SetCustomUsageType(customType string) *MapFlag[V] {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *MapFlag[V]) SetEnv(name string) *MapFlag[V] {
+	f.Env = name
+	return f
+}
+
+func (f *MapFlag[V]) SetCompletionFunc(fn CompletionFunc) *MapFlag[V] {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *MapFlag[V]) SetContextCompletionFunc(fn ContextCompletionFunc) *MapFlag[V] {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *MapFlag[V]) Register(cmd *Cmd, opts ...RegisterOption) (*map[string]V, error) {
+	ptr := new(map[string]V)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *MapFlag[V]) RegisterWithPtr(cmd *Cmd, ptr *map[string]V, opts ...RegisterOption) error {
+	// Validate default keys against constraints
+	if f.Default != nil {
+		for _, key := range sortedKeys(*f.Default) {
+			if err := f.checkKey(key); err != nil {
+				return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+			}
+			if err := validateDefaults(f.Name, f.Validator, (*f.Default)[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *MapFlag[V]) checkKey(key string) error {
+	if f.KeyEnumConstraint != nil && !slices.Contains(*f.KeyEnumConstraint, key) {
+		return newParseError(ConstraintViolation, f.Name, key, "Invalid '%s' key: %s (valid keys: %s)", f.Name, key, strings.Join(*f.KeyEnumConstraint, ", "))
+	}
+	if f.KeyRegexConstraint != nil && !f.KeyRegexConstraint.MatchString(key) {
+		return newParseError(ConstraintViolation, f.Name, key, "Invalid '%s' key: %s (must match regex: %s)", f.Name, key, f.KeyRegexConstraint.String())
+	}
+	return nil
+}
+
+func (f *MapFlag[V]) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+// set parses one or more key=value entries. All entries are validated before any
+// are stored, so an invalid value leaves the map unchanged.
+func (f *MapFlag[V]) set(value string) error {
+	entries := []string{value}
+	if f.Separator != nil {
+		entries = strings.Split(value, *f.Separator)
+	}
+
+	keys := make([]string, 0, len(entries))
+	vals := make([]V, 0, len(entries))
+	for _, entry := range entries {
+		key, rawVal, found := strings.Cut(entry, "=")
+		if !found || key == "" {
+			return newParseError(InvalidValue, f.Name, entry, "invalid entry for %s: %s (expected key=value)", f.Name, entry)
+		}
+		if err := f.checkKey(key); err != nil {
+			return err
+		}
+		val, err := f.parse(rawVal)
+		if err != nil {
+			return newParseError(InvalidValue, f.Name, rawVal, "invalid %s value for %s key %s: %s", f.valueType, f.Name, key, rawVal)
+		}
+		if err := validateValue(f.Name, f.Validator, val, entry); err != nil {
+			return err
+		}
+		if f.DuplicateKeys == DuplicateKeysError {
+			_, existing := (*f.Value)[key]
+			if (existing && !f.defaultsInPlace) || slices.Contains(keys, key) {
+				return newParseError(ConstraintViolation, f.Name, key, "duplicate key %q for %s", key, f.Name)
+			}
+		}
+		keys = append(keys, key)
+		vals = append(vals, val)
+	}
+
+	if f.defaultsInPlace || *f.Value == nil {
+		*f.Value = make(map[string]V, len(keys))
+		f.defaultsInPlace = false
+	}
+	for i, key := range keys {
+		(*f.Value)[key] = vals[i]
+	}
+	return nil
+}
+
+func (f *MapFlag[V]) applyDefault() {
+	*f.Value = make(map[string]V)
+	if f.Default != nil {
+		for k, v := range *f.Default {
+			(*f.Value)[k] = v
+		}
+	}
+	f.defaultsInPlace = true
+}
+
+func (f *MapFlag[V]) hasDefault() bool {
+	return f.Default != nil
+}
+
+// isSlice is true as map flags accumulate repeated values like slices.
+func (f *MapFlag[V]) isSlice() bool {
+	return true
+}
+
+func (f *MapFlag[V]) isVariadic() bool {
+	return false
+}
+
+func (f *MapFlag[V]) separator() *string {
+	return f.Separator
+}
+
+func (f *MapFlag[V]) usageType() string {
+	return "key=" + f.valueType
+}
+
+func (f *MapFlag[V]) dumpType() string {
+	result := "map[string]" + f.valueDumpType
+	if f.KeyEnumConstraint != nil {
+		result += fmt.Sprintf("{%s}", strings.Join(*f.KeyEnumConstraint, ","))
+	}
+	if f.KeyRegexConstraint != nil {
+		result += fmt.Sprintf("~%s", f.KeyRegexConstraint.String())
+	}
+	if f.Separator != nil {
+		result += fmt.Sprintf(" sep:%q", *f.Separator)
+	}
+	return result
+}
+
+// formatEntries renders m as sorted key=value entries.
+func (f *MapFlag[V]) formatEntries(m map[string]V) []string {
+	entries := make([]string, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		entries = append(entries, key+"="+f.format(m[key]))
+	}
+	return entries
+}
+
+func (f *MapFlag[V]) defaultString() string {
+	if f.Default != nil && len(*f.Default) > 0 {
+		return formatListDefault(f.formatEntries(*f.Default))
+	}
+	return ""
+}
+
+func (f *MapFlag[V]) dumpDefault() string {
+	if f.Default != nil {
+		return fmt.Sprintf("%v", f.formatEntries(*f.Default))
+	}
+	return "[]"
+}
+
+func (f *MapFlag[V]) dumpCurrent() string {
+	if f.Value != nil && len(*f.Value) > 0 {
+		return fmt.Sprintf("%v", f.formatEntries(*f.Value))
+	}
+	return ""
+}
+
+func (f *MapFlag[V]) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return f.formatEntries(*f.Value)
+}
+
+func (f *MapFlag[V]) rangeString() string {
+	return ""
+}
+
+func (f *MapFlag[V]) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *MapFlag[V]) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *MapFlag[V]) valuePtr() any {
+	return f.Value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,788 @@
+package ra
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// number is the set of sized numeric types handled by NumberFlag. int, int64 and
+// float64 have their own flag types.
+type number interface {
+	uint | uint8 | uint16 | uint32 | uint64 | int8 | int16 | int32 | float32
+}
+
+// NumberFlag is a flag of a sized numeric type, with range checking against the
+// type's bounds and optional min/max constraints.
+type NumberFlag[T number] struct {
+	Flag[T]
+	bounds bounds[T]
+
+	allowPrefixes *bool // integer types only; nil uses the command's default
+}
+
+// NumberSliceFlag is the slice counterpart of NumberFlag. Min/max apply to each element.
+type NumberSliceFlag[T number] struct {
+	BaseFlag
+	Separator *string
+	Variadic  bool
+	Default   *[]T
+	Value     *[]T
+	Validator func(T) error // run on each element
+
+	bounds          bounds[T]
+	allowPrefixes   *bool // integer types only; nil uses the command's default
+	defaultsInPlace bool  // true until the first user-provided value replaces the default
+}
+
+type UintFlag = NumberFlag[uint]
+type Uint8Flag = NumberFlag[uint8]
+type Uint16Flag = NumberFlag[uint16]
+type Uint32Flag = NumberFlag[uint32]
+type Uint64Flag = NumberFlag[uint64]
+type Int8Flag = NumberFlag[int8]
+type Int16Flag = NumberFlag[int16]
+type Int32Flag = NumberFlag[int32]
+type Float32Flag = NumberFlag[float32]
+
+type UintSliceFlag = NumberSliceFlag[uint]
+type Uint8SliceFlag = NumberSliceFlag[uint8]
+type Uint16SliceFlag = NumberSliceFlag[uint16]
+type Uint32SliceFlag = NumberSliceFlag[uint32]
+type Uint64SliceFlag = NumberSliceFlag[uint64]
+type Int8SliceFlag = NumberSliceFlag[int8]
+type Int16SliceFlag = NumberSliceFlag[int16]
+type Int32SliceFlag = NumberSliceFlag[int32]
+type Float32SliceFlag = NumberSliceFlag[float32]
+
+func newNumber[T number](name string) *NumberFlag[T] {
+	return &NumberFlag[T]{
+		Flag:   Flag[T]{BaseFlag: BaseFlag{Name: name, Optional: false}},
+		bounds: bounds[T]{compare: cmp.Compare[T]},
+	}
+}
+
+func newNumberSlice[T number](name string) *NumberSliceFlag[T] {
+	return &NumberSliceFlag[T]{
+		BaseFlag: BaseFlag{Name: name, Optional: false},
+		bounds:   bounds[T]{compare: cmp.Compare[T]},
+	}
+}
+
+func NewUint(name string) *UintFlag {
+	return newNumber[uint](name)
+}
+
+func NewUint8(name string) *Uint8Flag {
+	return newNumber[uint8](name)
+}
+
+func NewUint16(name string) *Uint16Flag {
+	return newNumber[uint16](name)
+}
+
+func NewUint32(name string) *Uint32Flag {
+	return newNumber[uint32](name)
+}
+
+func NewUint64(name string) *Uint64Flag {
+	return newNumber[uint64](name)
+}
+
+func NewInt8(name string) *Int8Flag {
+	return newNumber[int8](name)
+}
+
+func NewInt16(name string) *Int16Flag {
+	return newNumber[int16](name)
+}
+
+func NewInt32(name string) *Int32Flag {
+	return newNumber[int32](name)
+}
+
+func NewFloat32(name string) *Float32Flag {
+	return newNumber[float32](name)
+}
+
+func NewUintSlice(name string) *UintSliceFlag {
+	return newNumberSlice[uint](name)
+}
+
+func NewUint8Slice(name string) *Uint8SliceFlag {
+	return newNumberSlice[uint8](name)
+}
+
+func NewUint16Slice(name string) *Uint16SliceFlag {
+	return newNumberSlice[uint16](name)
+}
+
+func NewUint32Slice(name string) *Uint32SliceFlag {
+	return newNumberSlice[uint32](name)
+}
+
+func NewUint64Slice(name string) *Uint64SliceFlag {
+	return newNumberSlice[uint64](name)
+}
+
+func NewInt8Slice(name string) *Int8SliceFlag {
+	return newNumberSlice[int8](name)
+}
+
+func NewInt16Slice(name string) *Int16SliceFlag {
+	return newNumberSlice[int16](name)
+}
+
+func NewInt32Slice(name string) *Int32SliceFlag {
+	return newNumberSlice[int32](name)
+}
+
+func NewFloat32Slice(name string) *Float32SliceFlag {
+	return newNumberSlice[float32](name)
+}
+
+// numberKind describes how to parse and name a number type.
+type numberKind struct {
+	name   string // type name shown in help and dump, e.g. "uint8"
+	bits   int
+	float  bool
+	signed bool
+}
+
+func numberKindOf[T number]() numberKind {
+	switch any(*new(T)).(type) {
+	case uint:
+		return numberKind{name: "uint", bits: strconv.IntSize}
+	case uint8:
+		return numberKind{name: "uint8", bits: 8}
+	case uint16:
+		return numberKind{name: "uint16", bits: 16}
+	case uint32:
+		return numberKind{name: "uint32", bits: 32}
+	case uint64:
+		return numberKind{name: "uint64", bits: 64}
+	case int8:
+		return numberKind{name: "int8", bits: 8, signed: true}
+	case int16:
+		return numberKind{name: "int16", bits: 16, signed: true}
+	case int32:
+		return numberKind{name: "int32", bits: 32, signed: true}
+	default: // float32
+		return numberKind{name: "float32", bits: 32, float: true}
+	}
+}
+
+// parseNumber parses value as T, reporting out-of-range values like setIntValue.
+// allowPrefixes enables Go integer literal syntax for integer types (see intBase).
+func parseNumber[T number](name, value string, allowPrefixes bool) (T, error) {
+	kind := numberKindOf[T]()
+
+	if kind.float {
+		v, err := strconv.ParseFloat(value, kind.bits)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, newParseError(InvalidValue, name, value, "float overflow for %s: %s (value exceeds %s range)", name, value, kind.name)
+		} else if err != nil {
+			return 0, newParseError(InvalidValue, name, value, "invalid %s value for %s: %s", kind.name, name, value)
+		}
+		return T(v), nil
+	}
+
+	if kind.signed {
+		v, err := parseIntLiteral(value, allowPrefixes, kind.bits)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, newParseError(InvalidValue, name, value, "integer overflow for %s: %s (value exceeds %s range)", name, value, kind.name)
+		} else if err != nil {
+			return 0, newParseError(InvalidValue, name, value, "invalid %s value for %s: %s%s", kind.name, name, value, invalidIntSuffix(allowPrefixes))
+		}
+		return T(v), nil
+	}
+
+	v, err := strconv.ParseUint(value, intBase(allowPrefixes), kind.bits)
+	if err != nil {
+		// Negative integers are out of range for unsigned types rather than malformed
+		_, signedErr := parseIntLiteral(value, allowPrefixes, 64)
+		if errors.Is(err, strconv.ErrRange) || signedErr == nil || errors.Is(signedErr, strconv.ErrRange) {
+			return 0, newParseError(InvalidValue, name, value, "integer overflow for %s: %s (value exceeds %s range)", name, value, kind.name)
+		}
+		return 0, newParseError(InvalidValue, name, value, "invalid %s value for %s: %s%s", kind.name, name, value, invalidIntSuffix(allowPrefixes))
+	}
+	return T(v), nil
+}
+
+func formatNumber[T number](v T) string {
+	kind := numberKindOf[T]()
+	if kind.float {
+		return strconv.FormatFloat(float64(v), 'g', -1, kind.bits)
+	} else if kind.signed {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func parseNumberValue[T number](name, value string, allowPrefixes bool, b *bounds[T]) (T, error) {
+	v, err := parseNumber[T](name, value, allowPrefixes)
+	if err != nil {
+		return 0, err
+	}
+	if err := b.check(v, formatNumber[T]); err != nil {
+		return 0, newParseError(ConstraintViolation, name, value, "'%s' %w", name, err)
+	}
+	return v, nil
+}
+
+func (f *NumberFlag[T]) SetShort(s string) *NumberFlag[T] {
+	f.Short = s
+	return f
+}
+
+func (f *NumberFlag[T]) SetAliases(aliases ...string) *NumberFlag[T] {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *NumberFlag[T]) SetDeprecated(msg string) *NumberFlag[T] {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *NumberFlag[T]) SetDeprecatedAlias(alias, msg string) *NumberFlag[T] {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *NumberFlag[T]) SetUsage(u string) *NumberFlag[T] {
+	f.Usage = u
+	return f
+}
+
+func (f *NumberFlag[T]) SetDefault(v T) *NumberFlag[T] {
+	f.Default = &v
+	return f
+}
+
+func (f *NumberFlag[T]) SetOptional(b bool) *NumberFlag[T] {
+	f.Optional = b
+	return f
+}
+
+func (f *NumberFlag[T]) SetHidden(b bool) *NumberFlag[T] {
+	f.Hidden = b
+	return f
+}
+
+func (f *NumberFlag[T]) SetHiddenInShortHelp(b bool) *NumberFlag[T] {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *NumberFlag[T]) SetPositionalOnly(b bool) *NumberFlag[T] {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *NumberFlag[T]) SetFlagOnly(b bool) *NumberFlag[T] {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *NumberFlag[T]) SetExcludes(flags []string) *NumberFlag[T] {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *NumberFlag[T]) SetRequires(flags []string) *NumberFlag[T] {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *NumberFlag[T]) SetRequiredIf(flag, value string) *NumberFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *NumberFlag[T]) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *NumberFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *NumberFlag[T]) SetExcludesIf(flags []string, flag, value string) *NumberFlag[T] {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *NumberFlag[T]) SetValidator(fn func(v T) error) *NumberFlag[T] {
+	f.Validator = fn
+	return f
+}
+
+func (f *NumberFlag[T]) SetMin(min T, inclusive bool) *NumberFlag[T] {
+	f.bounds.setMin(min, inclusive)
+	return f
+}
+
+func (f *NumberFlag[T]) SetMax(max T, inclusive bool) *NumberFlag[T] {
+	f.bounds.setMax(max, inclusive)
+	return f
+}
+
+// SetAllowPrefixes accepts Go integer literal syntax, as for IntFlag.SetAllowPrefixes.
+// Registering a float32 flag with it set is a ProgrammingError.
+func (f *NumberFlag[T]) SetAllowPrefixes(allow bool) *NumberFlag[T] {
+	f.allowPrefixes = &allow
+	return f
+}
+
+func (f *NumberFlag[T]) SetCustomUsageType(customType string) *NumberFlag[T] {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *NumberFlag[T]) SetEnv(name string) *NumberFlag[T] {
+	f.Env = name
+	return f
+}
+
+func (f *NumberFlag[T]) SetCompletionFunc(fn CompletionFunc) *NumberFlag[T] {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *NumberFlag[T]) SetContextCompletionFunc(fn ContextCompletionFunc) *NumberFlag[T] {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *NumberFlag[T]) Register(cmd *Cmd, opts ...RegisterOption) (*T, error) {
+	ptr := new(T)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *NumberFlag[T]) RegisterWithPtr(cmd *Cmd, ptr *T, opts ...RegisterOption) error {
+	if kind := numberKindOf[T](); kind.float {
+		if err := checkAllowPrefixes(f.Name, f.allowPrefixes, kind.name); err != nil {
+			return err
+		}
+	}
+
+	// Validate default value against constraints
+	if f.Default != nil {
+		if err := f.bounds.check(*f.Default, formatNumber[T]); err != nil {
+			return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+// prefixesAllowed resolves whether prefixed integer literals are accepted, given
+// the command's default.
+func (f *NumberFlag[T]) prefixesAllowed(prefixesDefault bool) bool {
+	if f.allowPrefixes != nil {
+		return *f.allowPrefixes
+	}
+	return prefixesDefault
+}
+
+func (f *NumberFlag[T]) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *NumberFlag[T]) set(value string) error {
+	return f.setWithIntPrefixes(value, false)
+}
+
+func (f *NumberFlag[T]) setWithIntPrefixes(value string, prefixesDefault bool) error {
+	v, err := parseNumberValue(f.Name, value, f.prefixesAllowed(prefixesDefault), &f.bounds)
+	if err != nil {
+		return err
+	}
+	if err := validateValue(f.Name, f.Validator, v, value); err != nil {
+		return err
+	}
+	*f.Value = v
+	return nil
+}
+
+func (f *NumberFlag[T]) applyDefault() {
+	if f.Default != nil {
+		*f.Value = *f.Default
+	}
+}
+
+func (f *NumberFlag[T]) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *NumberFlag[T]) isSlice() bool {
+	return false
+}
+
+func (f *NumberFlag[T]) isVariadic() bool {
+	return false
+}
+
+func (f *NumberFlag[T]) separator() *string {
+	return nil
+}
+
+func (f *NumberFlag[T]) usageType() string {
+	return numberKindOf[T]().name
+}
+
+func (f *NumberFlag[T]) dumpType() string {
+	return numberKindOf[T]().name + f.bounds.dumpString(formatNumber[T])
+}
+
+func (f *NumberFlag[T]) defaultString() string {
+	if f.Default != nil {
+		return formatNumber(*f.Default)
+	}
+	return ""
+}
+
+func (f *NumberFlag[T]) dumpDefault() string {
+	if f.Default != nil {
+		return formatNumber(*f.Default)
+	}
+	return "none"
+}
+
+func (f *NumberFlag[T]) dumpCurrent() string {
+	if f.Value != nil && *f.Value != 0 {
+		return formatNumber(*f.Value)
+	}
+	return ""
+}
+
+func (f *NumberFlag[T]) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return []string{formatNumber(*f.Value)}
+}
+
+func (f *NumberFlag[T]) rangeString() string {
+	return f.bounds.rangeString(formatNumber[T])
+}
+
+func (f *NumberFlag[T]) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *NumberFlag[T]) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *NumberFlag[T]) valuePtr() any {
+	return f.Value
+}
+
+func (f *NumberSliceFlag[T]) SetShort(s string) *NumberSliceFlag[T] {
+	f.Short = s
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetAliases(aliases ...string) *NumberSliceFlag[T] {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetDeprecated(msg string) *NumberSliceFlag[T] {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetDeprecatedAlias(alias, msg string) *NumberSliceFlag[T] {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetUsage(u string) *NumberSliceFlag[T] {
+	f.Usage = u
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetDefault(v []T) *NumberSliceFlag[T] {
+	f.Default = &v
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetOptional(b bool) *NumberSliceFlag[T] {
+	f.Optional = b
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetHidden(b bool) *NumberSliceFlag[T] {
+	f.Hidden = b
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetHiddenInShortHelp(b bool) *NumberSliceFlag[T] {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetPositionalOnly(b bool) *NumberSliceFlag[T] {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetFlagOnly(b bool) *NumberSliceFlag[T] {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetExcludes(flags []string) *NumberSliceFlag[T] {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetRequires(flags []string) *NumberSliceFlag[T] {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *NumberSliceFlag[T]) SetRequiredIf(flag, value string) *NumberSliceFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *NumberSliceFlag[T]) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *NumberSliceFlag[T] {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *NumberSliceFlag[T]) SetExcludesIf(flags []string, flag, value string) *NumberSliceFlag[T] {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each element after the built-in constraints,
+// including the default elements at registration.
+func (f *NumberSliceFlag[T]) SetValidator(fn func(v T) error) *NumberSliceFlag[T] {
+	f.Validator = fn
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetSeparator(sep string) *NumberSliceFlag[T] {
+	f.Separator = &sep
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetVariadic(b bool) *NumberSliceFlag[T] {
+	f.Variadic = b
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetMin(min T, inclusive bool) *NumberSliceFlag[T] {
+	f.bounds.setMin(min, inclusive)
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetMax(max T, inclusive bool) *NumberSliceFlag[T] {
+	f.bounds.setMax(max, inclusive)
+	return f
+}
+
+// SetAllowPrefixes accepts Go integer literal syntax, as for IntFlag.SetAllowPrefixes.
+// Registering a float32 slice flag with it set is a ProgrammingError.
+func (f *NumberSliceFlag[T]) SetAllowPrefixes(allow bool) *NumberSliceFlag[T] {
+	f.allowPrefixes = &allow
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetCustomUsageType(customType string) *NumberSliceFlag[T] {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetEnv(name string) *NumberSliceFlag[T] {
+	f.Env = name
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetCompletionFunc(fn CompletionFunc) *NumberSliceFlag[T] {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *NumberSliceFlag[T]) SetContextCompletionFunc(fn ContextCompletionFunc) *NumberSliceFlag[T] {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *NumberSliceFlag[T]) Register(cmd *Cmd, opts ...RegisterOption) (*[]T, error) {
+	ptr := new([]T)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *NumberSliceFlag[T]) RegisterWithPtr(cmd *Cmd, ptr *[]T, opts ...RegisterOption) error {
+	if kind := numberKindOf[T](); kind.float {
+		if err := checkAllowPrefixes(f.Name, f.allowPrefixes, "[]"+kind.name); err != nil {
+			return err
+		}
+	}
+
+	// Validate default values against constraints
+	if f.Default != nil {
+		for _, v := range *f.Default {
+			if err := f.bounds.check(v, formatNumber[T]); err != nil {
+				return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+			}
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default...); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+// prefixesAllowed resolves whether prefixed integer literals are accepted, given
+// the command's default.
+func (f *NumberSliceFlag[T]) prefixesAllowed(prefixesDefault bool) bool {
+	if f.allowPrefixes != nil {
+		return *f.allowPrefixes
+	}
+	return prefixesDefault
+}
+
+func (f *NumberSliceFlag[T]) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *NumberSliceFlag[T]) set(value string) error {
+	return f.setWithIntPrefixes(value, false)
+}
+
+func (f *NumberSliceFlag[T]) setWithIntPrefixes(value string, prefixesDefault bool) error {
+	replace := f.defaultsInPlace
+	err := appendSliceValue(f.Value, value, f.Separator, replace, func(part string) (T, error) {
+		v, err := parseNumberValue(f.Name, part, f.prefixesAllowed(prefixesDefault), &f.bounds)
+		if err != nil {
+			return 0, err
+		}
+		return v, validateValue(f.Name, f.Validator, v, part)
+	})
+	if err == nil {
+		f.defaultsInPlace = false
+	}
+	return err
+}
+
+func (f *NumberSliceFlag[T]) applyDefault() {
+	if f.Default != nil {
+		*f.Value = append([]T{}, *f.Default...)
+	} else {
+		*f.Value = []T{}
+	}
+	f.defaultsInPlace = true
+}
+
+func (f *NumberSliceFlag[T]) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *NumberSliceFlag[T]) isSlice() bool {
+	return true
+}
+
+func (f *NumberSliceFlag[T]) isVariadic() bool {
+	return f.Variadic
+}
+
+func (f *NumberSliceFlag[T]) separator() *string {
+	return f.Separator
+}
+
+func (f *NumberSliceFlag[T]) usageType() string {
+	return sliceUsageType(numberKindOf[T]().name, f.Variadic)
+}
+
+func (f *NumberSliceFlag[T]) dumpType() string {
+	return sliceDumpType(numberKindOf[T]().name+f.bounds.dumpString(formatNumber[T]), f.Variadic, f.Separator)
+}
+
+func formatNumbers[T number](values []T) []string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, formatNumber(v))
+	}
+	return strs
+}
+
+func (f *NumberSliceFlag[T]) defaultString() string {
+	if f.Default != nil && len(*f.Default) > 0 {
+		return formatListDefault(formatNumbers(*f.Default))
+	}
+	return ""
+}
+
+func (f *NumberSliceFlag[T]) dumpDefault() string {
+	if f.Default != nil {
+		return fmt.Sprintf("%v", formatNumbers(*f.Default))
+	}
+	return "[]"
+}
+
+func (f *NumberSliceFlag[T]) dumpCurrent() string {
+	if f.Value != nil && len(*f.Value) > 0 {
+		return fmt.Sprintf("%v", formatNumbers(*f.Value))
+	}
+	return ""
+}
+
+func (f *NumberSliceFlag[T]) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return formatNumbers(*f.Value)
+}
+
+func (f *NumberSliceFlag[T]) rangeString() string {
+	return f.bounds.rangeString(formatNumber[T])
+}
+
+func (f *NumberSliceFlag[T]) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *NumberSliceFlag[T]) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *NumberSliceFlag[T]) valuePtr() any {
+	return f.Value
+}
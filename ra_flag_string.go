@@ -9,6 +9,8 @@ type StringFlag struct {
 	Flag[string]
 	EnumConstraint  *[]string      // if set, the value must be one of these
 	RegexConstraint *regexp.Regexp // if set, the value must match this regex
+
+	noOptDefault *string // value when given without one, e.g. --color; nil requires a value
 }
 
 func NewString(name string) *StringFlag {
@@ -19,6 +21,21 @@ func (f *StringFlag) SetShort(s string) *StringFlag {
 	return f
 }
 
+func (f *StringFlag) SetAliases(aliases ...string) *StringFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *StringFlag) SetDeprecated(msg string) *StringFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *StringFlag) SetDeprecatedAlias(alias, msg string) *StringFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
 func (f *StringFlag) SetUsage(u string) *StringFlag {
 	f.Usage = u
 	return f
@@ -64,6 +81,35 @@ func (f *StringFlag) SetRequires(flags []string) *StringFlag {
 	return f
 }
 
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *StringFlag) SetRequiredIf(flag, value string) *StringFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *StringFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *StringFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *StringFlag) SetExcludesIf(flags []string, flag, value string) *StringFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *StringFlag) SetValidator(fn func(v string) error) *StringFlag {
+	f.Validator = fn
+	return f
+}
+
 func (f *StringFlag) SetEnumConstraint(values []string) *StringFlag {
 	if len(values) == 0 {
 		f.EnumConstraint = nil
@@ -78,16 +124,33 @@ func (f *StringFlag) SetRegexConstraint(regex *regexp.Regexp) *StringFlag {
 	return f
 }
 
+// SetNoOptDefault makes the flag's value optional: given without one (--color
+// rather than --color=never) it takes v, and it never consumes the next argument.
+func (f *StringFlag) SetNoOptDefault(v string) *StringFlag {
+	f.noOptDefault = &v
+	return f
+}
+
 func (f *StringFlag) SetCustomUsageType(customType string) *StringFlag {
 	f.CustomUsageType = customType
 	return f
 }
 
+func (f *StringFlag) SetEnv(name string) *StringFlag {
+	f.Env = name
+	return f
+}
+
 func (f *StringFlag) SetCompletionFunc(fn CompletionFunc) *StringFlag {
 	f.CompletionFunc = fn
 	return f
 }
 
+func (f *StringFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *StringFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
 func (f *StringFlag) Register(cmd *Cmd, opts ...RegisterOption) (*string, error) {
 	ptr := new(string)
 	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
@@ -116,6 +179,17 @@ func (f *StringFlag) RegisterWithPtr(cmd *Cmd, ptr *string, opts ...RegisterOpti
 		}
 	}
 
+	// Validate no-opt default against constraints
+	if f.noOptDefault != nil {
+		if err := f.validateDefaultValue(*f.noOptDefault); err != nil {
+			return fmt.Errorf("invalid no-opt default value for flag %q: %w", f.Name, err)
+		}
+	}
+
+	if err := cmd.validateFlagAliases(f.Name, f.Aliases); err != nil {
+		return err
+	}
+
 	if _, err := cmd.checkForGlobalFlagOverride(f.Name, f.Short, regConf.global); err != nil {
 		return err
 	}
@@ -182,5 +256,11 @@ func (f *StringFlag) validateDefaultValue(value string) error {
 		}
 	}
 
+	// Check custom validator
+	if f.Validator != nil {
+		if err := f.Validator(value); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,352 @@
+package ra
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts accepted by NewTime when none are given.
+var DefaultTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// timeNow is the clock used for relative time values; replaced in tests.
+var timeNow = time.Now
+
+// TimeFlag is a time.Time flag, parsed with the first matching layout. Values without
+// a zone are interpreted in the flag's location (local time unless set).
+type TimeFlag struct {
+	Flag[time.Time]
+	layouts  []string
+	relative bool
+	location *time.Location
+	bounds   bounds[time.Time]
+}
+
+// NewTime creates a time flag accepting the given layouts (see time.Parse), or
+// DefaultTimeLayouts if none are given. The first layout is used to render the
+// default in help output.
+func NewTime(name string, layouts ...string) *TimeFlag {
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	return &TimeFlag{
+		Flag:    Flag[time.Time]{BaseFlag: BaseFlag{Name: name, Optional: false}},
+		layouts: append([]string{}, layouts...),
+		bounds:  bounds[time.Time]{compare: time.Time.Compare},
+	}
+}
+
+func (f *TimeFlag) SetShort(s string) *TimeFlag {
+	f.Short = s
+	return f
+}
+
+func (f *TimeFlag) SetAliases(aliases ...string) *TimeFlag {
+	f.Aliases = aliases
+	return f
+}
+
+func (f *TimeFlag) SetDeprecated(msg string) *TimeFlag {
+	f.Deprecated = msg
+	return f
+}
+
+func (f *TimeFlag) SetDeprecatedAlias(alias, msg string) *TimeFlag {
+	f.addDeprecatedAlias(alias, msg)
+	return f
+}
+
+func (f *TimeFlag) SetUsage(u string) *TimeFlag {
+	f.Usage = u
+	return f
+}
+
+func (f *TimeFlag) SetDefault(v time.Time) *TimeFlag {
+	f.Default = &v
+	return f
+}
+
+func (f *TimeFlag) SetOptional(b bool) *TimeFlag {
+	f.Optional = b
+	return f
+}
+
+func (f *TimeFlag) SetHidden(b bool) *TimeFlag {
+	f.Hidden = b
+	return f
+}
+
+func (f *TimeFlag) SetHiddenInShortHelp(b bool) *TimeFlag {
+	f.HiddenInShortHelp = b
+	return f
+}
+
+func (f *TimeFlag) SetPositionalOnly(b bool) *TimeFlag {
+	f.PositionalOnly = b
+	return f
+}
+
+func (f *TimeFlag) SetFlagOnly(b bool) *TimeFlag {
+	f.FlagOnly = b
+	return f
+}
+
+func (f *TimeFlag) SetExcludes(flags []string) *TimeFlag {
+	f.Excludes = &flags
+	return f
+}
+
+func (f *TimeFlag) SetRequires(flags []string) *TimeFlag {
+	f.Requires = &flags
+	return f
+}
+
+// SetRequiredIf makes the flag required when flag has the given value, e.g.
+// SetRequiredIf("mode", "remote"). The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *TimeFlag) SetRequiredIf(flag, value string) *TimeFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Flag: flag, Value: value})
+	return f
+}
+
+// SetRequiredWhen makes the flag required when test returns true after parsing;
+// desc describes the condition in help. The flag is otherwise optional, whatever
+// SetOptional says.
+func (f *TimeFlag) SetRequiredWhen(desc string, test func(cmd *Cmd) bool) *TimeFlag {
+	f.RequiredIf = append(f.RequiredIf, Condition{Desc: desc, Test: test})
+	return f
+}
+
+// SetExcludesIf excludes flags only while flag has the given value.
+func (f *TimeFlag) SetExcludesIf(flags []string, flag, value string) *TimeFlag {
+	f.ExcludesIf = append(f.ExcludesIf, ConditionalExcludes{Flags: flags, When: Condition{Flag: flag, Value: value}})
+	return f
+}
+
+// SetValidator sets a check run on each value after the built-in constraints,
+// and on the default at registration. Its error is reported as a ConstraintViolation.
+func (f *TimeFlag) SetValidator(fn func(v time.Time) error) *TimeFlag {
+	f.Validator = fn
+	return f
+}
+
+func (f *TimeFlag) SetMin(min time.Time, inclusive bool) *TimeFlag {
+	f.bounds.setMin(min, inclusive)
+	return f
+}
+
+func (f *TimeFlag) SetMax(max time.Time, inclusive bool) *TimeFlag {
+	f.bounds.setMax(max, inclusive)
+	return f
+}
+
+// SetRelative enables relative values in addition to the layouts: "now", "today",
+// "yesterday", "tomorrow" (the latter three at midnight), and signed durations
+// from now such as "-2h" or "+30m".
+func (f *TimeFlag) SetRelative(b bool) *TimeFlag {
+	f.relative = b
+	return f
+}
+
+// SetLocation sets the location for values without a zone (default time.Local).
+func (f *TimeFlag) SetLocation(loc *time.Location) *TimeFlag {
+	f.location = loc
+	return f
+}
+
+func (f *TimeFlag) SetCustomUsageType(customType string) *TimeFlag {
+	f.CustomUsageType = customType
+	return f
+}
+
+func (f *TimeFlag) SetEnv(name string) *TimeFlag {
+	f.Env = name
+	return f
+}
+
+func (f *TimeFlag) SetCompletionFunc(fn CompletionFunc) *TimeFlag {
+	f.CompletionFunc = fn
+	return f
+}
+
+func (f *TimeFlag) SetContextCompletionFunc(fn ContextCompletionFunc) *TimeFlag {
+	f.ContextCompletionFunc = fn
+	return f
+}
+
+func (f *TimeFlag) Register(cmd *Cmd, opts ...RegisterOption) (*time.Time, error) {
+	ptr := new(time.Time)
+	return ptr, f.RegisterWithPtr(cmd, ptr, opts...)
+}
+
+func (f *TimeFlag) RegisterWithPtr(cmd *Cmd, ptr *time.Time, opts ...RegisterOption) error {
+	// Validate default value against constraints
+	if f.Default != nil {
+		if err := f.bounds.check(*f.Default, f.format); err != nil {
+			return fmt.Errorf("invalid default value for flag %q: %w", f.Name, err)
+		}
+		if err := validateDefaults(f.Name, f.Validator, *f.Default); err != nil {
+			return err
+		}
+	}
+
+	// Create copy and set value pointer
+	flag := *f
+	flag.Value = ptr
+	return cmd.registerValueFlag(&flag, opts)
+}
+
+func (f *TimeFlag) loc() *time.Location {
+	if f.location != nil {
+		return f.location
+	}
+	return time.Local
+}
+
+// format renders t with the first layout.
+func (f *TimeFlag) format(t time.Time) string {
+	return t.Format(f.layouts[0])
+}
+
+func (f *TimeFlag) parse(value string) (time.Time, bool) {
+	for _, layout := range f.layouts {
+		if t, err := time.ParseInLocation(layout, value, f.loc()); err == nil {
+			return t, true
+		}
+	}
+
+	if !f.relative {
+		return time.Time{}, false
+	}
+
+	now := timeNow().In(f.loc())
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, f.loc())
+	switch strings.ToLower(value) {
+	case "now":
+		return now, true
+	case "today":
+		return midnight, true
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), true
+	}
+
+	// Require an explicit sign so e.g. "2h" isn't mistaken for a point in time
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		if d, err := time.ParseDuration(value); err == nil {
+			return now.Add(d), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (f *TimeFlag) getBase() *BaseFlag {
+	return &f.BaseFlag
+}
+
+func (f *TimeFlag) set(value string) error {
+	t, ok := f.parse(value)
+	if !ok {
+		accepted := strings.Join(f.layouts, ", ")
+		if f.relative {
+			accepted += ", now, today, yesterday, tomorrow, or an offset like -2h"
+		}
+		return newParseError(InvalidValue, f.Name, value, "invalid time value for %s: %s (accepted formats: %s)", f.Name, value, accepted)
+	}
+	if err := f.bounds.check(t, f.format); err != nil {
+		return newParseError(ConstraintViolation, f.Name, value, "'%s' %w", f.Name, err)
+	}
+	if err := validateValue(f.Name, f.Validator, t, value); err != nil {
+		return err
+	}
+	*f.Value = t
+	return nil
+}
+
+func (f *TimeFlag) applyDefault() {
+	if f.Default != nil {
+		*f.Value = *f.Default
+	}
+}
+
+func (f *TimeFlag) hasDefault() bool {
+	return f.Default != nil
+}
+
+func (f *TimeFlag) isSlice() bool {
+	return false
+}
+
+func (f *TimeFlag) isVariadic() bool {
+	return false
+}
+
+func (f *TimeFlag) separator() *string {
+	return nil
+}
+
+func (f *TimeFlag) usageType() string {
+	return "time"
+}
+
+func (f *TimeFlag) dumpType() string {
+	result := "time" + f.bounds.dumpString(f.format)
+	if f.relative {
+		result += "(relative)"
+	}
+	return result
+}
+
+func (f *TimeFlag) defaultString() string {
+	if f.Default != nil {
+		return f.format(*f.Default)
+	}
+	return ""
+}
+
+func (f *TimeFlag) dumpDefault() string {
+	if f.Default != nil {
+		return f.format(*f.Default)
+	}
+	return "none"
+}
+
+func (f *TimeFlag) dumpCurrent() string {
+	if f.Value != nil && !f.Value.IsZero() {
+		return f.format(*f.Value)
+	}
+	return ""
+}
+
+func (f *TimeFlag) valueStrings() []string {
+	if f.Value == nil {
+		return nil
+	}
+	return []string{f.format(*f.Value)}
+}
+
+func (f *TimeFlag) rangeString() string {
+	return f.bounds.rangeString(f.format)
+}
+
+func (f *TimeFlag) copyFlag() any {
+	copy := *f
+	return &copy
+}
+
+func (f *TimeFlag) cloneWith(clones map[any]any) any {
+	clone := *f
+	clone.Value = cloneValue(f.Value, clones)
+	return &clone
+}
+
+func (f *TimeFlag) valuePtr() any {
+	return f.Value
+}
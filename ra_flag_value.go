@@ -0,0 +1,182 @@
+package ra
+
+import (
+	"fmt"
+	"strings"
+)
+
+// valueFlag is implemented by flag types that carry their own parsing and
+// formatting. The parser, usage, dump and completion handle any valueFlag through
+// this interface instead of needing a dedicated case per concrete type.
+type valueFlag interface {
+	getBase() *BaseFlag
+	// set parses value and assigns it; slice flags append it instead, splitting on
+	// their separator and replacing the default on the first user-provided value.
+	set(value string) error
+	// applyDefault resets the value to the default before parsing.
+	applyDefault()
+	hasDefault() bool
+	isSlice() bool
+	isVariadic() bool
+	separator() *string
+	usageType() string     // type shown in help, e.g. "str" or "[strs...]"
+	dumpType() string      // type shown in dump output, including constraints
+	defaultString() string // default shown in help, "" to omit
+	dumpDefault() string   // default shown in dump output
+	dumpCurrent() string   // current value shown in dump output, "" to omit
+	rangeString() string   // range constraint shown in help, "" if none
+	// valueStrings returns the current value, or each element of a slice or map,
+	// formatted as in help, for conditions such as SetRequiredIf.
+	valueStrings() []string
+	copyFlag() any
+	// cloneWith copies the flag with a Value of its own, for Cmd.Parse (see cloneFlag).
+	cloneWith(clones map[any]any) any
+	valuePtr() any // the Value pointer, for ParseResult
+}
+
+// registerValueFlag adds an already-copied valueFlag (with its Value pointer set)
+// to cmd, with the same validation as the built-in flag types.
+func (c *Cmd) registerValueFlag(flag valueFlag, opts []RegisterOption) error {
+	regConf := &registerConfig{}
+	for _, opt := range opts {
+		opt(regConf)
+	}
+
+	base := flag.getBase()
+
+	// Validate flag name is not empty
+	if base.Name == "" {
+		return fmt.Errorf("flag name cannot be empty")
+	}
+
+	// Validate mutually exclusive configuration
+	if base.PositionalOnly && base.FlagOnly {
+		return fmt.Errorf("flag %q cannot be both PositionalOnly and FlagOnly (mutually exclusive)", base.Name)
+	}
+
+	if err := c.validateFlagAliases(base.Name, base.Aliases); err != nil {
+		return err
+	}
+
+	if _, err := c.checkForGlobalFlagOverride(base.Name, base.Short, regConf.global); err != nil {
+		return err
+	}
+
+	if regConf.global {
+		c.globalFlags = append(c.globalFlags, base.Name)
+	}
+
+	base.BypassValidation = regConf.bypassValidation
+
+	// Global flags should be flag-only (not positional)
+	if regConf.global {
+		base.FlagOnly = true
+		if !flag.isSlice() {
+			base.Optional = true
+		}
+	}
+
+	// Add to short mapping
+	if base.Short != "" {
+		if _, exists := c.shortToName[base.Short]; exists {
+			return fmt.Errorf("short flag %q already defined", base.Short)
+		}
+		c.shortToName[base.Short] = base.Name
+	}
+
+	c.flags[base.Name] = flag
+	if !base.FlagOnly {
+		// Check for positional-only after variadic error
+		if base.PositionalOnly {
+			if err := c.validatePositionalOnlyAfterVariadic(base.Name); err != nil {
+				return err
+			}
+		}
+		c.positional = append(c.positional, base.Name)
+	} else {
+		c.nonPositional = append(c.nonPositional, base.Name)
+	}
+
+	return nil
+}
+
+// appendSliceValue parses value, split on sep if set, and appends the results to
+// dst. If replace is true, dst is emptied first (the first user-provided value
+// replaces the default).
+func appendSliceValue[T any](dst *[]T, value string, sep *string, replace bool, parse func(string) (T, error)) error {
+	parts := []string{value}
+	if sep != nil {
+		parts = strings.Split(value, *sep)
+	}
+
+	parsed := make([]T, 0, len(parts))
+	for _, part := range parts {
+		val, err := parse(part)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, val)
+	}
+
+	if replace {
+		*dst = make([]T, 0, len(parsed))
+	}
+	*dst = append(*dst, parsed...)
+	return nil
+}
+
+// parseValueSliceFlag consumes the value(s) following a slice valueFlag at index.
+// Variadic flags consume until the next flag; negative numbers are values (see
+// parseIntSliceFlag).
+func (c *Cmd) parseValueSliceFlag(args []string, index int, f valueFlag, cfg *parseCfg) (int, error) {
+	if !f.isVariadic() {
+		// Single value
+		if index+1 >= len(args) {
+			return 1, nil // Empty slice
+		}
+		return 2, c.setValue(f, args[index+1])
+	}
+
+	numberShortsMode := c.hasNumberShorts()
+	consumed := 1
+	for i := index + 1; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") && (numberShortsMode || !isNegativeNumberToken(arg)) {
+			if !cfg.variadicUnknownFlags || c.wouldParseAsFlag(arg, cfg) {
+				break
+			}
+		}
+		if err := c.setValue(f, arg); err != nil {
+			return 0, err
+		}
+		consumed++
+	}
+
+	return consumed, nil
+}
+
+// formatListDefault renders slice values for help output, e.g. "[a, b]".
+func formatListDefault(values []string) string {
+	return fmt.Sprintf("[%s]", strings.Join(values, ", "))
+}
+
+// sliceUsageType renders the help type for a slice flag from its element type,
+// e.g. "strs" or "[strs...]" when variadic.
+func sliceUsageType(elemType string, variadic bool) string {
+	if variadic {
+		return fmt.Sprintf("[%ss...]", elemType)
+	}
+	return elemType + "s"
+}
+
+// sliceDumpType renders the dump type for a slice flag, e.g. `[]url.URL(variadic) sep:","`.
+func sliceDumpType(elemType string, variadic bool, sep *string) string {
+	result := "[]" + elemType
+	if variadic {
+		result += "(variadic)"
+	}
+	if sep != nil {
+		result += fmt.Sprintf(" sep:%q", *sep)
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package ra
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagGroupKind is the rule a FlagGroup enforces on how many of its flags are set.
+type FlagGroupKind int
+
+const (
+	GroupExactlyOne FlagGroupKind = iota
+	GroupAtLeastOne
+	GroupAtMostOne
+	GroupAllOrNone
+)
+
+// String returns the rule as used in errors and dump, e.g. "exactly one".
+func (k FlagGroupKind) String() string {
+	switch k {
+	case GroupExactlyOne:
+		return "exactly one"
+	case GroupAtLeastOne:
+		return "at least one"
+	case GroupAtMostOne:
+		return "at most one"
+	case GroupAllOrNone:
+		return "all or none"
+	}
+	return "unknown"
+}
+
+// FlagGroup constrains how many of several flags may be set together. Create one
+// with ExactlyOne, AtLeastOne, AtMostOne or AllOrNone and add it with
+// Cmd.AddFlagGroup.
+type FlagGroup struct {
+	Kind  FlagGroupKind
+	Flags []string
+}
+
+// ExactlyOne requires that exactly one of the flags is set.
+func ExactlyOne(flags ...string) FlagGroup {
+	return FlagGroup{Kind: GroupExactlyOne, Flags: flags}
+}
+
+// AtLeastOne requires that one or more of the flags are set.
+func AtLeastOne(flags ...string) FlagGroup {
+	return FlagGroup{Kind: GroupAtLeastOne, Flags: flags}
+}
+
+// AtMostOne allows no more than one of the flags to be set.
+func AtMostOne(flags ...string) FlagGroup {
+	return FlagGroup{Kind: GroupAtMostOne, Flags: flags}
+}
+
+// AllOrNone requires that the flags are either all set or all unset.
+func AllOrNone(flags ...string) FlagGroup {
+	return FlagGroup{Kind: GroupAllOrNone, Flags: flags}
+}
+
+// AddFlagGroup adds a constraint across several of the command's flags. Flags in a
+// group aren't individually required; the group decides which must be set.
+func (c *Cmd) AddFlagGroup(group FlagGroup) *Cmd {
+	c.flagGroups = append(c.flagGroups, group)
+	return c
+}
+
+// validateFlagGroupReferences checks that groups are well formed and name
+// registered flags.
+func (c *Cmd) validateFlagGroupReferences(validFlags map[string]bool) error {
+	for _, group := range c.flagGroups {
+		if len(group.Flags) < 2 {
+			return NewProgrammingError(fmt.Sprintf("Flag group '%s' needs at least two flags", group.Kind))
+		}
+		for _, name := range group.Flags {
+			if !validFlags[name] {
+				return NewProgrammingError(fmt.Sprintf("Undefined flag '%s'", name))
+			}
+		}
+	}
+	return nil
+}
+
+// checkFlagGroups checks each group's rule, returning the first violation unless
+// cfg is collecting errors.
+func (c *Cmd) checkFlagGroups(cfg *parseCfg) error {
+	for _, group := range c.flagGroups {
+		if err := cfg.report(c.checkFlagGroup(group)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFlagGroup returns an error if the group's rule isn't met. As with
+// exclusions, only flags set by the user count, not defaults.
+func (c *Cmd) checkFlagGroup(group FlagGroup) error {
+	var set []string
+	for _, name := range group.Flags {
+		if c.flagExplicitlySetForExclusion(name) {
+			set = append(set, name)
+		}
+	}
+
+	members := quoteFlagNames(group.Flags, "or")
+	switch group.Kind {
+	case GroupExactlyOne:
+		if len(set) == 0 {
+			return newParseError(MissingRequired, "", "", "Invalid args: exactly one of %s must be set", members)
+		}
+		if len(set) > 1 {
+			return newParseError(Excluded, "", "", "Invalid args: exactly one of %s must be set, but %s were set", members, quoteFlagNames(set, "and"))
+		}
+	case GroupAtLeastOne:
+		if len(set) == 0 {
+			return newParseError(MissingRequired, "", "", "Invalid args: at least one of %s must be set", members)
+		}
+	case GroupAtMostOne:
+		if len(set) > 1 {
+			return newParseError(Excluded, "", "", "Invalid args: at most one of %s may be set, but %s were set", members, quoteFlagNames(set, "and"))
+		}
+	case GroupAllOrNone:
+		if len(set) > 0 && len(set) < len(group.Flags) {
+			var unset []string
+			for _, name := range group.Flags {
+				if !c.flagExplicitlySetForExclusion(name) {
+					unset = append(unset, name)
+				}
+			}
+			verb := "was"
+			if len(unset) > 1 {
+				verb = "were"
+			}
+			return newParseError(RequiresMissing, "", "", "Invalid args: all or none of %s must be set, but %s %s not set",
+				quoteFlagNames(group.Flags, "and"), quoteFlagNames(unset, "and"), verb)
+		}
+	}
+	return nil
+}
+
+// isFlagInGroup reports whether the flag belongs to any of the command's groups.
+func (c *Cmd) isFlagInGroup(name string) bool {
+	for _, group := range c.flagGroups {
+		for _, member := range group.Flags {
+			if c.resolveFlagAlias(member) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagGroupsSynopsis renders the command's groups for the usage synopsis, e.g.
+// "{--file | --url}" for exactly one, "{--a | --b}..." for at least one,
+// "[--a | --b]" for at most one and "[--a --b]" for all or none.
+func (c *Cmd) flagGroupsSynopsis(isLongHelp bool) []string {
+	var groups []string
+	for _, group := range c.flagGroups {
+		var names []string
+		for _, member := range group.Flags {
+			name := c.resolveFlagAlias(member)
+			base := getBaseFlag(c.flags[name])
+			if base == nil || !base.isVisible(isLongHelp) {
+				continue
+			}
+			if base.PositionalOnly {
+				names = append(names, name)
+			} else {
+				names = append(names, "--"+name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		switch group.Kind {
+		case GroupExactlyOne:
+			groups = append(groups, "{"+strings.Join(names, " | ")+"}")
+		case GroupAtLeastOne:
+			groups = append(groups, "{"+strings.Join(names, " | ")+"}...")
+		case GroupAtMostOne:
+			groups = append(groups, "["+strings.Join(names, " | ")+"]")
+		case GroupAllOrNone:
+			groups = append(groups, "["+strings.Join(names, " ")+"]")
+		}
+	}
+	return groups
+}
+
+// quoteFlagNames lists names as "'a'", "'a' and 'b'" or "'a', 'b' and 'c'", with
+// conj in place of "and".
+func quoteFlagNames(names []string, conj string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	last := len(quoted) - 1
+	return strings.Join(quoted[:last], ", ") + " " + conj + " " + quoted[last]
+}
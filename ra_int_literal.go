@@ -0,0 +1,75 @@
+package ra
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// intPrefixForms describes the integer literals accepted when prefixes are allowed,
+// for use in error messages.
+const intPrefixForms = "decimal, 0x hex, 0o or 0 octal, 0b binary, with optional _ separators"
+
+// intBase returns the strconv base for integer flags: 0 (Go integer literal syntax,
+// e.g. 0x1F, 0o755, 0b1010, 1_000_000) when prefixes are allowed, otherwise 10.
+func intBase(allowPrefixes bool) int {
+	if allowPrefixes {
+		return 0
+	}
+	return 10
+}
+
+// allowsIntPrefixes resolves whether an integer flag accepts prefixed literals: the
+// flag's own setting if set, otherwise the command-wide default.
+func (c *Cmd) allowsIntPrefixes(flagSetting *bool) bool {
+	if flagSetting != nil {
+		return *flagSetting
+	}
+	return c.intPrefixes
+}
+
+// invalidIntSuffix is appended to invalid integer errors, listing the accepted forms
+// when prefixes are allowed.
+func invalidIntSuffix(allowPrefixes bool) string {
+	if allowPrefixes {
+		return " (accepted forms: " + intPrefixForms + ")"
+	}
+	return ""
+}
+
+// intPrefixFlag is implemented by valueFlags with integer values, which have no
+// access to the command while parsing and so are passed its default with each value.
+type intPrefixFlag interface {
+	setWithIntPrefixes(value string, prefixesDefault bool) error
+}
+
+// setValue assigns value to f, passing integer flags this command's default for
+// prefixed literals.
+func (c *Cmd) setValue(f valueFlag, value string) error {
+	if f, ok := f.(intPrefixFlag); ok {
+		return f.setWithIntPrefixes(value, c.intPrefixes)
+	}
+	return f.set(value)
+}
+
+// resolveIntPrefixes sets this command's default for prefixed integer literals from
+// its own setting, falling back to the one inherited from its parent.
+func (c *Cmd) resolveIntPrefixes(cfg *parseCfg) {
+	c.intPrefixes = cfg.intPrefixes
+	if c.allowIntPrefixes != nil {
+		c.intPrefixes = *c.allowIntPrefixes
+	}
+}
+
+// checkAllowPrefixes rejects SetAllowPrefixes on a flag whose values, of type
+// kind, aren't integers, where it would silently do nothing.
+func checkAllowPrefixes(name string, allowPrefixes *bool, kind string) error {
+	if allowPrefixes == nil {
+		return nil
+	}
+	return NewProgrammingError(fmt.Sprintf("flag %q: SetAllowPrefixes only applies to integer flags, not %s", name, kind))
+}
+
+// parseIntLiteral is strconv.ParseInt with the base chosen by intBase.
+func parseIntLiteral(value string, allowPrefixes bool, bitSize int) (int64, error) {
+	return strconv.ParseInt(value, intBase(allowPrefixes), bitSize)
+}
@@ -0,0 +1,49 @@
+package ra
+
+import (
+	"strings"
+)
+
+// negationPrefix is prepended to a negatable bool flag's name to turn it off.
+const negationPrefix = "no-"
+
+// negatableDefault returns this command's default for bool flags' SetNegatable:
+// its own setting if set, otherwise the one inherited from its parent.
+func (c *Cmd) negatableDefault() bool {
+	if c.negatableBools != nil {
+		return *c.negatableBools
+	}
+	return c.parentNegatable
+}
+
+// isNegatable resolves whether a bool flag also accepts --no-<name>: the flag's
+// own setting if set, otherwise the command-wide default.
+func (c *Cmd) isNegatable(f *BoolFlag) bool {
+	if f.negatable != nil {
+		return *f.negatable
+	}
+	return c.negatableDefault()
+}
+
+// lookupNegatedFlag resolves a long flag name of the form no-<name> to the
+// negatable bool flag it turns off.
+func (c *Cmd) lookupNegatedFlag(name string) (*BoolFlag, bool) {
+	target, found := strings.CutPrefix(name, negationPrefix)
+	if !found {
+		return nil, false
+	}
+	f, ok := c.flags[c.resolveFlagAlias(target)].(*BoolFlag)
+	if !ok || !c.isNegatable(f) {
+		return nil, false
+	}
+	return f, true
+}
+
+// longFlagDisplayName is the long name shown in help, e.g. "[no-]color" for
+// negatable bool flags.
+func (c *Cmd) longFlagDisplayName(flag any, name string) string {
+	if f, ok := flag.(*BoolFlag); ok && c.isNegatable(f) {
+		return "[" + negationPrefix + "]" + name
+	}
+	return name
+}
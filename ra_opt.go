@@ -1,5 +1,7 @@
 package ra
 
+import "context"
+
 type RegisterOption func(*registerConfig)
 
 type registerConfig struct {
@@ -23,6 +25,28 @@ type parseCfg struct {
 	ignoreUnknown        bool
 	variadicUnknownFlags bool
 	dump                 bool
+	argOffset            int                            // index of args[0] within the args given to the root command
+	intPrefixes          bool                           // prefixed integer literal default inherited from the parent command
+	negatableBools       bool                           // negatable bools default inherited from the parent command
+	deprecationErrors    bool                           // if true, using a deprecated flag or command is an error
+	deprecationHook      func(cmd *Cmd, warning string) // root command's Deprecated parse hook
+	noSuggestions        bool                           // suggestions setting inherited from the parent command, inverted
+	suggestionDistance   int                            // suggestion distance inherited from the parent command; 0 for the default
+	abbreviations        bool                           // if true, unique prefixes of long flag names are accepted
+	subCmdAbbreviations  bool                           // if true, unique prefixes of subcommand names are accepted
+	collectErrors        bool                           // if true, user errors are gathered into ParseErrors
+	errs                 ParseErrors                    // user errors gathered so far when collectErrors is set
+	ctx                  context.Context                // context from ExecuteContext, for hooks and completion funcs
+	handleSignals        bool                           // if true, ExecuteContext cancels its context on SIGINT or SIGTERM
+
+	// config layer
+	configPath      string         // config file to load flag values from
+	configDecoder   ConfigDecoder  // decoder for configPath (JSON if nil)
+	configValues    map[string]any // pre-decoded config values, used instead of configPath
+	strictConfig    bool           // if true, unknown config keys are an error
+	configLoaded    bool           // whether configSection has been resolved for this parse
+	configSection   map[string]any // config values for the command being parsed
+	configKeyPrefix string         // dotted path of configSection within the config, for errors
 }
 
 type ParseOpt func(*parseCfg)
@@ -44,3 +68,93 @@ func WithDump(dump bool) ParseOpt {
 		c.dump = dump
 	}
 }
+
+// WithAllowAbbreviations lets a long flag be given by any prefix of its name that
+// is unique among the command's visible flags, e.g. --verb for --verbose.
+func WithAllowAbbreviations(allow bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.abbreviations = allow
+	}
+}
+
+// WithAllowSubCmdAbbreviations lets a subcommand be invoked by any prefix of its
+// name that is unique among the command's visible subcommands.
+func WithAllowSubCmdAbbreviations(allow bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.subCmdAbbreviations = allow
+	}
+}
+
+// WithCollectErrors makes a parse carry on past user errors (unknown flags, bad
+// values, constraint violations, missing required flags) and return them all
+// together as ParseErrors, so they can be fixed in one go.
+func WithCollectErrors(collect bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.collectErrors = collect
+	}
+}
+
+// withIntPrefixes passes a command's prefixed integer literal default on to the
+// subcommand being parsed.
+func withIntPrefixes(allow bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.intPrefixes = allow
+	}
+}
+
+// WithDeprecationErrors makes using a deprecated flag, alias or command an error
+// instead of a warning, e.g. for CI.
+func WithDeprecationErrors(enable bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.deprecationErrors = enable
+	}
+}
+
+// withDeprecationHook passes the root command's Deprecated parse hook on to the
+// subcommand being parsed.
+func withDeprecationHook(hook func(cmd *Cmd, warning string)) ParseOpt {
+	return func(c *parseCfg) {
+		c.deprecationHook = hook
+	}
+}
+
+// withNegatableBools passes a command's negatable bools default on to the
+// subcommand being parsed.
+func withNegatableBools(negatable bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.negatableBools = negatable
+	}
+}
+
+// withSuggestions passes a command's suggestion settings on to the subcommand
+// being parsed.
+func withSuggestions(enable bool, distance int) ParseOpt {
+	return func(c *parseCfg) {
+		c.noSuggestions = !enable
+		c.suggestionDistance = distance
+	}
+}
+
+// withArgOffset tells a subcommand where its args start within the root command's
+// args, so value sources report indices into what the user passed to Parse.
+func withArgOffset(offset int) ParseOpt {
+	return func(c *parseCfg) {
+		c.argOffset = offset
+	}
+}
+
+// WithSignalHandling makes ExecuteContext cancel the context it gives handlers
+// and hooks on SIGINT or SIGTERM, then exit with 128 plus the signal's number
+// (130 for SIGINT) once the handler returns. A second signal exits at once.
+func WithSignalHandling(enable bool) ParseOpt {
+	return func(c *parseCfg) {
+		c.handleSignals = enable
+	}
+}
+
+// withContext passes ExecuteContext's context on to the commands being parsed.
+func withContext(ctx context.Context) ParseOpt {
+	return func(c *parseCfg) {
+		c.ctx = ctx
+	}
+}
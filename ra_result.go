@@ -0,0 +1,72 @@
+package ra
+
+import "fmt"
+
+// ParseResult is the outcome of Parse: flag values, which were configured, and
+// which commands were invoked. It isn't changed after Parse returns, so it can
+// be shared between goroutines.
+type ParseResult struct {
+	cmd  *Cmd   // parsed copy of the command tree
+	path []*Cmd // cmd and the subcommands invoked under it, outermost first
+}
+
+// Parse parses args like ParseOrError, but into a ParseResult rather than the
+// pointers returned at registration and c's own state, which are left untouched.
+// Unlike the other ways of parsing, a Cmd can be given to Parse from several
+// goroutines at once, and needs no ResetParseState between runs. Parse hooks are
+// called with a copy of the command. RA_COLOR is applied by the other ways of
+// parsing, not by Parse, as it sets global state.
+func (c *Cmd) Parse(args []string, opts ...ParseOpt) (*ParseResult, error) {
+	clone := c.clone(make(map[any]any))
+	if err := clone.parseOrError(args, opts...); err != nil {
+		return nil, err
+	}
+	return &ParseResult{cmd: clone, path: clone.invokedPath()}, nil
+}
+
+// Get returns the value of the flag called name, taken from the deepest invoked
+// command that has it, e.g. Get[int](res, "port"). T must match the flag's type:
+// int for an IntFlag, []string for a StringSliceFlag, map[string]int for a MapFlag
+// of ints, and so on. It panics with a *ProgrammingError if there's no such flag
+// or T doesn't match.
+func Get[T any](res *ParseResult, name string) T {
+	for i := len(res.path) - 1; i >= 0; i-- {
+		flag, exists := res.path[i].flags[res.path[i].resolveFlagAlias(name)]
+		if !exists {
+			continue
+		}
+		ptr, ok := flagValuePtr(flag).(*T)
+		if !ok {
+			var zero T
+			panic(NewProgrammingError(fmt.Sprintf("flag %q is not of type %T", name, zero)))
+		}
+		return *ptr
+	}
+	panic(NewProgrammingError(fmt.Sprintf("flag %q is not defined on the invoked commands", name)))
+}
+
+// Configured returns whether the flag called name was given explicitly, on the
+// command line, by environment variable or in a config file.
+func (r *ParseResult) Configured(name string) bool {
+	return r.cmd.Configured(name)
+}
+
+// InvokedPath returns the names of the root command and the subcommands invoked
+// under it, outermost first, e.g. ["app", "remote", "add"].
+func (r *ParseResult) InvokedPath() []string {
+	names := make([]string, 0, len(r.path))
+	for _, cmd := range r.path {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+// UnknownArgs returns the args that matched nothing when parsing with
+// WithIgnoreUnknown(true), from every invoked command, outermost first.
+func (r *ParseResult) UnknownArgs() []string {
+	var args []string
+	for _, cmd := range r.path {
+		args = append(args, cmd.unknownArgs...)
+	}
+	return args
+}
@@ -0,0 +1,200 @@
+package ra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ExitError is returned by a handler or hook to exit Execute with a specific code.
+// Its message is printed unless Err is nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// NewExitError creates an ExitError exiting with code, printing err if non-nil.
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// Execute runs the command like ExecuteContext.
+func (c *Cmd) Execute(ctx context.Context, args []string, opts ...ParseOpt) {
+	c.ExecuteContext(ctx, args, opts...)
+}
+
+// ExecuteContext parses args like ParseOrExit, then runs the handler set with
+// SetRun on the deepest command invoked, along with the PreRun and PostRun hooks
+// of it and its parents. If a handler or hook fails, its error is printed to
+// stderr and the program exits through the exit func: with the code of an error
+// with an ExitCode() int method, such as ExitError or *exec.ExitError, or else 1.
+//
+// ctx is given to handlers, PreRun and PostRun hooks and ContextCompletionFuncs,
+// and other hooks can get it from Cmd.Context. See WithSignalHandling for
+// cancelling it on SIGINT or SIGTERM.
+func (c *Cmd) ExecuteContext(ctx context.Context, args []string, opts ...ParseOpt) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := &parseCfg{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var stop func() os.Signal
+	if cfg.handleSignals {
+		ctx, stop = cancelOnSignal(ctx)
+		defer stop()
+	}
+
+	err := c.execute(ctx, args, append(opts[:len(opts):len(opts)], withContext(ctx))...)
+	if stop != nil {
+		// The handler was interrupted, so whatever it returned, exit as the signal asked
+		if sig := stop(); sig != nil {
+			osExit(signalExitCode(sig))
+			return
+		}
+	}
+	if err != nil {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			if e, ok := err.(*ExitError); !ok || e.Err != nil {
+				fmt.Fprintln(stderrWriter, err.Error())
+			}
+			osExit(exitErr.ExitCode())
+			return
+		}
+		fmt.Fprintln(stderrWriter, err.Error())
+		osExit(1)
+	}
+}
+
+// execute parses args and runs the invoked command, returning any error from its
+// handler or hooks. Parse errors and a missing handler exit here.
+func (c *Cmd) execute(ctx context.Context, args []string, opts ...ParseOpt) error {
+	if !c.parseOrExit(args, opts...) {
+		return nil
+	}
+
+	path := c.invokedPath()
+	cmd := path[len(path)-1]
+	if cmd.run == nil {
+		if len(cmd.subCmds) > 0 {
+			// Invoked without one of its subcommands, which do the work
+			fmt.Fprint(stderrWriter, cmd.GenerateShortUsage())
+		} else {
+			fmt.Fprintln(stderrWriter, NewProgrammingError(fmt.Sprintf("no handler set for command %q", cmd.name)))
+		}
+		osExit(1)
+		return nil
+	}
+	return runPath(ctx, path)
+}
+
+// runPath runs the handler of the last command in path, between the PreRun and
+// PostRun hooks of every command in it.
+func runPath(ctx context.Context, path []*Cmd) error {
+	cmd := path[len(path)-1]
+	for _, p := range path {
+		if p.parseHooks != nil && p.parseHooks.PreRun != nil {
+			if err := p.parseHooks.PreRun(ctx, cmd); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cmd.run(ctx, cmd); err != nil {
+		return err
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if p := path[i]; p.parseHooks != nil && p.parseHooks.PostRun != nil {
+			if err := p.parseHooks.PostRun(ctx, cmd); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// invokedPath returns c followed by the chain of subcommands invoked under it in
+// the last parse, outermost first.
+func (c *Cmd) invokedPath() []*Cmd {
+	path := []*Cmd{c}
+	for _, subCmd := range c.subCmds {
+		if subCmd.used != nil && *subCmd.used {
+			return append(path, subCmd.invokedPath()...)
+		}
+	}
+	return path
+}
+
+// cancelOnSignal returns a copy of ctx that's cancelled on SIGINT or SIGTERM, and a
+// func to stop listening that returns the signal received, if any. A second
+// signal exits immediately.
+func cancelOnSignal(ctx context.Context) (context.Context, func() os.Signal) {
+	ctx, cancel := context.WithCancel(ctx)
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	var mu sync.Mutex
+	var received os.Signal
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				mu.Lock()
+				first := received == nil
+				if first {
+					received = sig
+				}
+				mu.Unlock()
+				if !first {
+					osExit(signalExitCode(sig))
+					return
+				}
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() os.Signal {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+			cancel()
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+// signalExitCode is the conventional exit code for a process ended by sig.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 130
+}
@@ -0,0 +1,111 @@
+package ra
+
+// SourceKind identifies where a flag's value came from.
+type SourceKind int
+
+const (
+	SourceUnset      SourceKind = iota // not configured, and no default
+	SourceDefault                      // not configured, holds its Default
+	SourceFlag                         // given as a named flag on the command line
+	SourcePositional                   // given as a positional argument on the command line
+	SourceEnv                          // read from the flag's Env variable
+	SourceConfig                       // read from the config file or config values
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceFlag:
+		return "flag"
+	case SourcePositional:
+		return "positional"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	}
+	return "unset"
+}
+
+// ValueSource describes where a flag's value came from.
+type ValueSource struct {
+	Kind SourceKind
+	// Raw tokens the value was parsed from: the command-line args (including the flag
+	// itself, e.g. ["--port", "8080"]), the environment value, or the config value(s).
+	Tokens []string
+	// Index into the args passed to Parse of the first command-line token, or -1 if
+	// the value didn't come from the command line.
+	ArgIndex int
+	// Environment variable name or dotted config key, for SourceEnv and SourceConfig.
+	Key string
+}
+
+// Source reports where the named flag's value came from in the last parse. name
+// may be an alias. Like Configured, flags of invoked subcommands are also searched.
+func (c *Cmd) Source(name string) ValueSource {
+	if source := c.findSource(name); source != nil {
+		return *source
+	}
+
+	if flag, exists := c.flags[c.resolveFlagAlias(name)]; exists && c.flagHasDefault(flag) {
+		return ValueSource{Kind: SourceDefault, ArgIndex: -1}
+	}
+	return ValueSource{Kind: SourceUnset, ArgIndex: -1}
+}
+
+func (c *Cmd) findSource(name string) *ValueSource {
+	// Aliases are resolved per command, as a subcommand's flags have their own
+	if flagName := c.resolveFlagAlias(name); c.configured[flagName] {
+		if source, exists := c.sources[flagName]; exists {
+			return source
+		}
+	}
+
+	for _, subCmd := range c.subCmds {
+		if subCmd.used != nil && *subCmd.used {
+			if source := subCmd.findSource(name); source != nil {
+				return source
+			}
+		}
+	}
+	return nil
+}
+
+// markConfigured records that a flag was set from the argument currently being
+// parsed. Its source is filled in by recordArgSources once the parser knows how
+// many tokens the argument consumed.
+func (c *Cmd) markConfigured(name string) {
+	c.noteDeprecatedFlag(name)
+	c.configured[name] = true
+	c.pendingSources = append(c.pendingSources, name)
+}
+
+// recordArgSources attributes the given command-line tokens to every flag marked
+// configured since the last call. Repeated or variadic flags accumulate tokens,
+// keeping the index of the first.
+func (c *Cmd) recordArgSources(kind SourceKind, tokens []string, argIndex int) {
+	for _, name := range c.pendingSources {
+		if source, exists := c.sources[name]; exists {
+			source.Tokens = append(source.Tokens, tokens...)
+			continue
+		}
+		c.sources[name] = &ValueSource{
+			Kind:     kind,
+			Tokens:   append([]string{}, tokens...),
+			ArgIndex: argIndex,
+		}
+	}
+	c.pendingSources = nil
+}
+
+// setSource records a flag's source when it was set from outside the command line.
+func (c *Cmd) setSource(name string, kind SourceKind, key string, tokens []string) {
+	c.configured[name] = true
+	c.sources[name] = &ValueSource{
+		Kind:     kind,
+		Tokens:   tokens,
+		ArgIndex: -1,
+		Key:      key,
+	}
+}
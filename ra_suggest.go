@@ -0,0 +1,152 @@
+package ra
+
+import (
+	"slices"
+	"strings"
+)
+
+// defaultSuggestionDistance is the furthest edit distance at which a name is
+// suggested for a mistyped one, unless changed with SetSuggestionDistance.
+const defaultSuggestionDistance = 2
+
+// maxSuggestions caps how many names a "did you mean" hint lists.
+const maxSuggestions = 3
+
+// resolveSuggestions sets this command's suggestion settings from its own, falling
+// back to those inherited from its parent.
+func (c *Cmd) resolveSuggestions(cfg *parseCfg) {
+	c.suggest = !cfg.noSuggestions
+	c.suggestMax = cfg.suggestionDistance
+	if c.suggestMax == 0 {
+		c.suggestMax = defaultSuggestionDistance
+	}
+	if c.suggestions != nil {
+		c.suggest = *c.suggestions
+	}
+	if c.suggestDist != nil {
+		if *c.suggestDist < 1 {
+			c.suggest = false
+		} else {
+			c.suggestMax = *c.suggestDist
+		}
+	}
+}
+
+// suggestFor returns the candidates within the command's suggestion distance of
+// input, closest first, or nil if suggestions are disabled.
+func (c *Cmd) suggestFor(input string, candidates []string) []string {
+	if !c.suggest || input == "" {
+		return nil
+	}
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		if candidate == input {
+			continue
+		}
+		distance := editDistance(strings.ToLower(input), strings.ToLower(candidate))
+		if distance <= c.suggestMax {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	var suggestions []string
+	for _, m := range matches {
+		if !slices.Contains(suggestions, m.name) {
+			suggestions = append(suggestions, m.name)
+		}
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+	}
+	return suggestions
+}
+
+// suggestFlags returns suggestions for an unknown long flag name among the names,
+// aliases and --no- forms of the command's visible flags.
+func (c *Cmd) suggestFlags(name string) []string {
+	var candidates []string
+	for flagName, flag := range c.flags {
+		base := getBaseFlag(flag)
+		if base == nil || base.Hidden || base.Deprecated != "" || base.PositionalOnly {
+			continue
+		}
+		candidates = append(candidates, flagName)
+		candidates = append(candidates, base.visibleAliases()...)
+		if f, ok := flag.(*BoolFlag); ok && c.isNegatable(f) {
+			candidates = append(candidates, negationPrefix+flagName)
+		}
+	}
+	return c.suggestFor(name, candidates)
+}
+
+// suggestSubCmds returns suggestions for an unknown subcommand among the names and
+// aliases of the command's visible subcommands.
+func (c *Cmd) suggestSubCmds(name string) []string {
+	var candidates []string
+	for subName, subCmd := range c.subCmds {
+		if subCmd.hidden || subCmd.deprecated != "" {
+			continue
+		}
+		candidates = append(candidates, subName)
+		candidates = append(candidates, subCmd.visibleAliases()...)
+	}
+	return c.suggestFor(name, candidates)
+}
+
+// didYouMean formats suggestions as a hint to append to an error, e.g.
+// ", did you mean --verbose?", or returns "" if there are none.
+func didYouMean(suggestions []string, prefix string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return ", did you mean " + joinOr(suggestions, prefix) + "?"
+}
+
+// joinOr lists names, each with prefix, as "a", "a or b" or "a, b or c".
+func joinOr(names []string, prefix string) string {
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		prefixed[i] = prefix + name
+	}
+	if len(prefixed) == 1 {
+		return prefixed[0]
+	}
+	last := len(prefixed) - 1
+	return strings.Join(prefixed[:last], ", ") + " or " + prefixed[last]
+}
+
+// editDistance is the Levenshtein distance between a and b, counting an adjacent
+// transposition as a single edit.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev2 := make([]int, len(br)+1)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(br)]
+}
@@ -2,11 +2,16 @@ package ra
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"net/netip"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -4898,3 +4903,2224 @@ func Test_VariadicSliceWithDefaultValues_ShouldReplaceNotAppend(t *testing.T) {
 		// Current behavior would be: []int{0, 1, 5, 10}
 	})
 }
+
+func Test_Env_FillsUnsetFlag(t *testing.T) {
+	t.Setenv("MYAPP_PORT", "9090")
+	fs := NewCmd("test")
+
+	port, err := NewInt("port").SetEnv("MYAPP_PORT").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 9090, *port)
+	assert.True(t, fs.Configured("port"))
+}
+
+func Test_Env_CommandLineTakesPrecedence(t *testing.T) {
+	t.Setenv("MYAPP_PORT", "9090")
+	fs := NewCmd("test")
+
+	port, err := NewInt("port").SetEnv("MYAPP_PORT").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--port", "8080"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 8080, *port)
+}
+
+func Test_Env_TakesPrecedenceOverDefault(t *testing.T) {
+	t.Setenv("MYAPP_REGION", "eu-west-1")
+	fs := NewCmd("test")
+
+	region, err := NewString("region").SetDefault("us-east-1").SetEnv("MYAPP_REGION").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "eu-west-1", *region)
+}
+
+func Test_Env_UnsetOrEmptyFallsBackToDefault(t *testing.T) {
+	t.Setenv("MYAPP_EMPTY", "")
+	fs := NewCmd("test")
+
+	region, err := NewString("region").SetDefault("us-east-1").SetEnv("MYAPP_UNSET_VAR").Register(fs)
+	assert.NoError(t, err)
+	zone, err := NewString("zone").SetDefault("a").SetEnv("MYAPP_EMPTY").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "us-east-1", *region)
+	assert.Equal(t, "a", *zone)
+	assert.False(t, fs.Configured("region"))
+	assert.False(t, fs.Configured("zone"))
+}
+
+func Test_Env_SatisfiesRequiredFlag(t *testing.T) {
+	t.Setenv("MYAPP_TOKEN", "secret")
+	fs := NewCmd("test")
+
+	token, err := NewString("token").SetFlagOnly(true).SetEnv("MYAPP_TOKEN").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "secret", *token)
+}
+
+func Test_Env_ValueIsValidated(t *testing.T) {
+	t.Run("int conversion", func(t *testing.T) {
+		t.Setenv("MYAPP_PORT", "abc")
+		fs := NewCmd("test")
+		_, err := NewInt("port").SetOptional(true).SetEnv("MYAPP_PORT").Register(fs)
+		assert.NoError(t, err)
+
+		parseErr := fs.ParseOrError([]string{})
+		assert.EqualError(t, parseErr, "invalid integer value for port: abc (from environment variable MYAPP_PORT)")
+	})
+
+	t.Run("range constraint", func(t *testing.T) {
+		t.Setenv("MYAPP_PORT", "70000")
+		fs := NewCmd("test")
+		_, err := NewInt("port").SetOptional(true).SetMax(65535, true).SetEnv("MYAPP_PORT").Register(fs)
+		assert.NoError(t, err)
+
+		parseErr := fs.ParseOrError([]string{})
+		assert.EqualError(t, parseErr, "'port' value 70000 is > maximum 65535 (from environment variable MYAPP_PORT)")
+	})
+
+	t.Run("enum constraint", func(t *testing.T) {
+		t.Setenv("MYAPP_FORMAT", "csv")
+		fs := NewCmd("test")
+		_, err := NewString("format").
+			SetOptional(true).
+			SetEnumConstraint([]string{"json", "yaml"}).
+			SetEnv("MYAPP_FORMAT").
+			Register(fs)
+		assert.NoError(t, err)
+
+		parseErr := fs.ParseOrError([]string{})
+		assert.Error(t, parseErr)
+		assert.Contains(t, parseErr.Error(), "Invalid 'format' value: csv")
+	})
+}
+
+func Test_Env_SliceSplitsOnSeparator(t *testing.T) {
+	t.Setenv("MYAPP_TAGS", "a,b,c")
+	t.Setenv("MYAPP_IDS", "1,2")
+	fs := NewCmd("test")
+
+	tags, err := NewStringSlice("tags").SetSeparator(",").SetEnv("MYAPP_TAGS").Register(fs)
+	assert.NoError(t, err)
+	ids, err := NewIntSlice("ids").SetSeparator(",").SetDefault([]int{9}).SetEnv("MYAPP_IDS").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []string{"a", "b", "c"}, *tags)
+	assert.Equal(t, []int{1, 2}, *ids)
+}
+
+func Test_Env_SliceNotMergedWithCommandLine(t *testing.T) {
+	t.Setenv("MYAPP_TAGS", "a,b")
+	fs := NewCmd("test")
+
+	tags, err := NewStringSlice("tags").SetSeparator(",").SetEnv("MYAPP_TAGS").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--tags", "x"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []string{"x"}, *tags)
+}
+
+func Test_Env_BoolFlag(t *testing.T) {
+	t.Setenv("MYAPP_VERBOSE", "1")
+	fs := NewCmd("test")
+
+	verbose, err := NewBool("verbose").SetEnv("MYAPP_VERBOSE").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.True(t, *verbose)
+}
+
+func Test_Env_SatisfiesRelationalConstraints(t *testing.T) {
+	t.Setenv("MYAPP_USER", "alice")
+	fs := NewCmd("test")
+
+	_, err := NewString("password").SetOptional(true).SetRequires([]string{"user"}).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("user").SetOptional(true).SetEnv("MYAPP_USER").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--password", "pw"})
+	assert.Nil(t, parseErr)
+}
+
+func Test_Env_SubcommandAndGlobalFlags(t *testing.T) {
+	t.Setenv("MYAPP_PROFILE", "prod")
+	t.Setenv("MYAPP_REPLICAS", "3")
+	fs := NewCmd("test")
+
+	profile, err := NewString("profile").SetOptional(true).SetEnv("MYAPP_PROFILE").Register(fs, WithGlobal(true))
+	assert.NoError(t, err)
+
+	deploy := NewCmd("deploy")
+	replicas, err := NewInt("replicas").SetEnv("MYAPP_REPLICAS").Register(deploy)
+	assert.NoError(t, err)
+	_, err = fs.RegisterCmd(deploy)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"deploy"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "prod", *profile)
+	assert.Equal(t, 3, *replicas)
+}
+
+func Test_Source_CommandLine(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewString("file").Register(fs)
+	assert.NoError(t, err)
+	_, err = NewInt("port").SetShort("p").Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("host").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("verbose").SetShort("v").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--host=example.com", "input.txt", "-p", "8080", "-v"})
+	assert.Nil(t, parseErr)
+
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"--host=example.com"}, ArgIndex: 0}, fs.Source("host"))
+	assert.Equal(t, ValueSource{Kind: SourcePositional, Tokens: []string{"input.txt"}, ArgIndex: 1}, fs.Source("file"))
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"-p", "8080"}, ArgIndex: 2}, fs.Source("port"))
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"-v"}, ArgIndex: 4}, fs.Source("verbose"))
+}
+
+func Test_Source_RepeatedAndVariadicAccumulateTokens(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewStringSlice("files").SetVariadic(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewStringSlice("tag").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"a.txt", "b.txt", "--tag", "x", "--tag", "y"})
+	assert.Nil(t, parseErr)
+
+	assert.Equal(t, ValueSource{Kind: SourcePositional, Tokens: []string{"a.txt", "b.txt"}, ArgIndex: 0}, fs.Source("files"))
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"--tag", "x", "--tag", "y"}, ArgIndex: 2}, fs.Source("tag"))
+}
+
+func Test_Source_EnvConfigDefaultAndUnset(t *testing.T) {
+	t.Setenv("MYAPP_REGION", "eu-west-1")
+	fs := NewCmd("test")
+
+	_, err := NewString("region").SetEnv("MYAPP_REGION").Register(fs)
+	assert.NoError(t, err)
+	_, err = NewInt("replicas").Register(fs)
+	assert.NoError(t, err)
+	_, err = NewInt("port").SetDefault(80).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("name").SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{}, WithConfigValues(map[string]any{"replicas": 3}))
+	assert.Nil(t, parseErr)
+
+	assert.Equal(t, ValueSource{Kind: SourceEnv, Tokens: []string{"eu-west-1"}, ArgIndex: -1, Key: "MYAPP_REGION"}, fs.Source("region"))
+	assert.Equal(t, ValueSource{Kind: SourceConfig, Tokens: []string{"3"}, ArgIndex: -1, Key: "replicas"}, fs.Source("replicas"))
+	assert.Equal(t, ValueSource{Kind: SourceDefault, ArgIndex: -1}, fs.Source("port"))
+	assert.Equal(t, ValueSource{Kind: SourceUnset, ArgIndex: -1}, fs.Source("name"))
+	assert.Equal(t, ValueSource{Kind: SourceUnset, ArgIndex: -1}, fs.Source("nonexistent"))
+	assert.Equal(t, "env", fs.Source("region").Kind.String())
+}
+
+func Test_Source_SubcommandArgIndicesAreAbsolute(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewBool("verbose").SetShort("v").Register(fs, WithGlobal(true))
+	assert.NoError(t, err)
+
+	deploy := NewCmd("deploy")
+	_, err = NewString("target").Register(deploy)
+	assert.NoError(t, err)
+	_, err = fs.RegisterCmd(deploy)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"-v", "deploy", "prod"})
+	assert.Nil(t, parseErr)
+
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"-v"}, ArgIndex: 0}, fs.Source("verbose"))
+	assert.Equal(t, ValueSource{Kind: SourcePositional, Tokens: []string{"prod"}, ArgIndex: 2}, fs.Source("target"))
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"-v"}, ArgIndex: 0}, deploy.Source("verbose"))
+}
+
+func Test_Source_ResetOnReparse(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewString("name").SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+
+	assert.Nil(t, fs.ParseOrError([]string{"--name", "alice"}))
+	assert.Equal(t, SourceFlag, fs.Source("name").Kind)
+
+	fs.ResetParseState()
+	assert.Nil(t, fs.ParseOrError([]string{}))
+	assert.Equal(t, SourceUnset, fs.Source("name").Kind)
+}
+
+func Test_Source_Aliases(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewString("path").SetAliases("dir").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewInt("level").SetAliases("lvl").SetDefault(1).Register(fs)
+	assert.NoError(t, err)
+
+	deploy := NewCmd("deploy")
+	_, err = NewString("target").SetAliases("to").SetFlagOnly(true).Register(deploy)
+	assert.NoError(t, err)
+	_, err = fs.RegisterCmd(deploy)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--dir", "a", "deploy", "--to", "prod"})
+	assert.Nil(t, parseErr)
+
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"--dir", "a"}, ArgIndex: 0}, fs.Source("dir"))
+	assert.Equal(t, fs.Source("path"), fs.Source("dir"))
+	assert.Equal(t, SourceDefault, fs.Source("lvl").Kind)
+	assert.Equal(t, ValueSource{Kind: SourceFlag, Tokens: []string{"--to", "prod"}, ArgIndex: 3}, fs.Source("to"))
+}
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+)
+
+func parseLogLevel(s string) (logLevel, error) {
+	switch s {
+	case "debug":
+		return levelDebug, nil
+	case "info":
+		return levelInfo, nil
+	}
+	return 0, fmt.Errorf("must be debug or info")
+}
+
+func formatLogLevel(l logLevel) string {
+	return [...]string{"debug", "info"}[l]
+}
+
+func Test_Custom_ParsesFlagAndPositional(t *testing.T) {
+	fs := NewCmd("test")
+
+	prefix, err := NewCustom("prefix", netip.ParsePrefix, netip.Prefix.String).Register(fs)
+	assert.NoError(t, err)
+	level, err := NewCustom("level", parseLogLevel, formatLogLevel).
+		SetShort("l").
+		SetDefault(levelInfo).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"10.0.0.0/8", "-l", "debug"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), *prefix)
+	assert.Equal(t, levelDebug, *level)
+
+	fs = NewCmd("test")
+	level, err = NewCustom("level", parseLogLevel, formatLogLevel).SetDefault(levelInfo).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr = fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, levelInfo, *level)
+	assert.False(t, fs.Configured("level"))
+}
+
+func Test_Custom_InvalidValue(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewCustom("level", parseLogLevel, formatLogLevel).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--level=trace"})
+	assert.EqualError(t, parseErr, "invalid loglevel value for level: trace (must be debug or info)")
+}
+
+func Test_Custom_RequiredAndConstraints(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewCustom("level", parseLogLevel, formatLogLevel).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("quiet").SetExcludes([]string{"level"}).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.EqualError(t, parseErr, "Missing required arguments: [level]")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--quiet", "--level", "info"})
+	assert.EqualError(t, parseErr, "Invalid args: 'quiet' excludes 'level', but 'level' was set")
+}
+
+func Test_Custom_Slice(t *testing.T) {
+	fs := NewCmd("test")
+
+	prefixes, err := NewCustomSlice("allow", netip.ParsePrefix, netip.Prefix.String).
+		SetSeparator(",").
+		SetDefault([]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+	levels, err := NewCustomSlice("levels", parseLogLevel, formatLogLevel).SetVariadic(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"debug", "info", "--allow", "10.0.0.0/8,192.168.0.0/16", "--allow", "::1/128"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.0.0/16"),
+		netip.MustParsePrefix("::1/128"),
+	}, *prefixes)
+	assert.Equal(t, []logLevel{levelDebug, levelInfo}, *levels)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}, *prefixes)
+	assert.Equal(t, []logLevel{}, *levels)
+}
+
+func Test_Custom_EnvAndConfig(t *testing.T) {
+	t.Setenv("MYAPP_LEVEL", "debug")
+	fs := NewCmd("test")
+
+	level, err := NewCustom("level", parseLogLevel, formatLogLevel).SetEnv("MYAPP_LEVEL").Register(fs)
+	assert.NoError(t, err)
+	prefixes, err := NewCustomSlice("allow", netip.ParsePrefix, netip.Prefix.String).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{}, WithConfigValues(map[string]any{"allow": []any{"10.0.0.0/8"}}))
+	assert.Nil(t, parseErr)
+	assert.Equal(t, levelDebug, *level)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, *prefixes)
+}
+
+func Test_Custom_GlobalFlag(t *testing.T) {
+	fs := NewCmd("test")
+
+	level, err := NewCustom("level", parseLogLevel, formatLogLevel).Register(fs, WithGlobal(true))
+	assert.NoError(t, err)
+
+	sub := NewCmd("sub")
+	_, err = fs.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"sub", "--level", "debug"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, levelDebug, *level)
+}
+
+func Test_Custom_MissingParseFunc(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewCustom[int]("n", nil, nil).Register(fs)
+	assert.EqualError(t, err, `flag "n" has no parse function`)
+}
+
+func Test_Duration_ParsesWithMinMax(t *testing.T) {
+	fs := NewCmd("test")
+
+	timeout, err := NewDuration("timeout").
+		SetDefault(30*time.Second).
+		SetMin(time.Second, true).
+		SetMax(time.Hour, false).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--timeout", "1m30s"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 90*time.Second, *timeout)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 30*time.Second, *timeout)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--timeout", "500ms"})
+	assert.EqualError(t, parseErr, "'timeout' value 500ms is < minimum 1s")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--timeout", "1h"})
+	assert.EqualError(t, parseErr, "'timeout' value 1h is >= maximum (exclusive) 1h")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--timeout", "10"})
+	assert.EqualError(t, parseErr, "invalid duration value for timeout: 10 (expected e.g. 300ms, 1.5h, 2h45m)")
+}
+
+func Test_Duration_InvalidDefault(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewDuration("timeout").SetDefault(0).SetMin(time.Second, true).Register(fs)
+	assert.EqualError(t, err, `invalid default value for flag "timeout": value 0s is < minimum 1s`)
+}
+
+func Test_Duration_Slice(t *testing.T) {
+	fs := NewCmd("test")
+
+	backoff, err := NewDurationSlice("backoff").
+		SetSeparator(",").
+		SetDefault([]time.Duration{time.Second}).
+		SetMax(time.Minute, true).
+		Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--backoff", "1s,5s", "--backoff", "30s"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}, *backoff)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []time.Duration{time.Second}, *backoff)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--backoff", "1s,2m"})
+	assert.EqualError(t, parseErr, "'backoff' value 2m is > maximum 1m")
+}
+
+func Test_Time_ParsesDefaultLayouts(t *testing.T) {
+	fs := NewCmd("test")
+
+	since, err := NewTime("since").SetLocation(time.UTC).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"2024-03-01T12:30:00+02:00"})
+	assert.Nil(t, parseErr)
+	assert.True(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC).Equal(*since))
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"2024-03-01"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *since)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"yesterday"})
+	assert.EqualError(t, parseErr, "invalid time value for since: yesterday (accepted formats: "+
+		"2006-01-02T15:04:05Z07:00, 2006-01-02T15:04:05, 2006-01-02 15:04:05, 2006-01-02)")
+}
+
+func Test_Time_CustomLayoutsAndBounds(t *testing.T) {
+	fs := NewCmd("test")
+
+	day, err := NewTime("day", "02/01/2006").
+		SetLocation(time.UTC).
+		SetMin(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true).
+		Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"15/06/2024"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), *day)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"31/12/2023"})
+	assert.EqualError(t, parseErr, "'day' value 31/12/2023 is < minimum 01/01/2024")
+}
+
+func Test_Time_Relative(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	fs := NewCmd("test")
+	since, err := NewTime("since").SetRelative(true).SetLocation(time.UTC).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	tests := map[string]time.Time{
+		"now":        now,
+		"today":      time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		"yesterday":  time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+		"tomorrow":   time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		"-2h":        now.Add(-2 * time.Hour),
+		"+30m":       now.Add(30 * time.Minute),
+		"2024-01-02": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	for input, expected := range tests {
+		fs.ResetParseState()
+		parseErr := fs.ParseOrError([]string{"--since", input})
+		assert.Nil(t, parseErr, input)
+		assert.Equal(t, expected, *since, input)
+	}
+
+	fs.ResetParseState()
+	parseErr := fs.ParseOrError([]string{"--since", "2h"})
+	assert.ErrorContains(t, parseErr, "now, today, yesterday, tomorrow, or an offset like -2h")
+}
+
+func Test_Number_SizedTypes(t *testing.T) {
+	fs := NewCmd("test")
+
+	port, err := NewUint16("port").SetDefault(8080).SetMin(1024, true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	mode, err := NewUint8("mode").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	offset, err := NewInt32("offset").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	ratio, err := NewFloat32("ratio").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	size, err := NewUint64("size").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{
+		"--port", "65535", "--mode", "255", "--offset", "-5", "--ratio", "0.25", "--size", "18446744073709551615",
+	})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, uint16(65535), *port)
+	assert.Equal(t, uint8(255), *mode)
+	assert.Equal(t, int32(-5), *offset)
+	assert.Equal(t, float32(0.25), *ratio)
+	assert.Equal(t, uint64(18446744073709551615), *size)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, uint16(8080), *port)
+}
+
+func Test_Number_RangeErrors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--port", "65536"}, "integer overflow for port: 65536 (value exceeds uint16 range)"},
+		{[]string{"--port", "-1"}, "integer overflow for port: -1 (value exceeds uint16 range)"},
+		{[]string{"--port", "http"}, "invalid uint16 value for port: http"},
+		{[]string{"--port", "80"}, "'port' value 80 is < minimum 1024"},
+		{[]string{"--level", "128"}, "integer overflow for level: 128 (value exceeds int8 range)"},
+		{[]string{"--ratio", "1e39"}, "float overflow for ratio: 1e39 (value exceeds float32 range)"},
+	}
+	for _, tt := range tests {
+		fs := NewCmd("test")
+		_, err := NewUint16("port").SetMin(1024, true).SetOptional(true).SetFlagOnly(true).Register(fs)
+		assert.NoError(t, err)
+		_, err = NewInt8("level").SetOptional(true).SetFlagOnly(true).Register(fs)
+		assert.NoError(t, err)
+		_, err = NewFloat32("ratio").SetOptional(true).SetFlagOnly(true).Register(fs)
+		assert.NoError(t, err)
+
+		parseErr := fs.ParseOrError(tt.args)
+		assert.EqualError(t, parseErr, tt.expected, tt.args)
+	}
+
+	fs := NewCmd("test")
+	_, err := NewUint8("mode").SetDefault(5).SetMax(3, true).Register(fs)
+	assert.EqualError(t, err, `invalid default value for flag "mode": value 5 is > maximum 3`)
+}
+
+func Test_Number_Slices(t *testing.T) {
+	fs := NewCmd("test")
+
+	ports, err := NewUint16Slice("ports").SetSeparator(",").SetMin(1, true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	deltas, err := NewInt16Slice("deltas").SetVariadic(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"-3", "7", "--ports", "80,443"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []uint16{80, 443}, *ports)
+	assert.Equal(t, []int16{-3, 7}, *deltas)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--ports", "80,0"})
+	assert.EqualError(t, parseErr, "'ports' value 0 is < minimum 1")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--ports", "80,70000"})
+	assert.EqualError(t, parseErr, "integer overflow for ports: 70000 (value exceeds uint16 range)")
+}
+
+func Test_IntPrefixes_PerFlag(t *testing.T) {
+	fs := NewCmd("test")
+
+	mode, err := NewInt("mode").SetAllowPrefixes(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	mask, err := NewInt64("mask").SetAllowPrefixes(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	count, err := NewInt("count").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--mode", "0o755", "--mask", "0b1010"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 0o755, *mode)
+	assert.Equal(t, int64(10), *mask)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--mode", "0x1F", "--mask", "1_000_000"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 31, *mode)
+	assert.Equal(t, int64(1000000), *mask)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--mode", "0x1F", "--mask", "1", "--count", "0x10"})
+	assert.EqualError(t, parseErr, "invalid integer value for count: 0x10")
+	assert.Equal(t, 0, *count)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--mode", "0x1G", "--mask", "1"})
+	assert.EqualError(t, parseErr, "invalid integer value for mode: 0x1G "+
+		"(accepted forms: decimal, 0x hex, 0o or 0 octal, 0b binary, with optional _ separators)")
+}
+
+func Test_IntPrefixes_RejectedForNonIntegerFlags(t *testing.T) {
+	fs := NewCmd("test")
+	var progErr *ProgrammingError
+
+	_, err := NewStringSlice("names").SetAllowPrefixes(true).Register(fs)
+	assert.ErrorAs(t, err, &progErr)
+	assert.EqualError(t, err, `flag "names": SetAllowPrefixes only applies to integer flags, not []string`)
+	_, err = NewFloat64Slice("ratios").SetAllowPrefixes(false).Register(fs)
+	assert.EqualError(t, err, `flag "ratios": SetAllowPrefixes only applies to integer flags, not []float64`)
+	_, err = NewBoolSlice("bits").SetAllowPrefixes(true).Register(fs)
+	assert.EqualError(t, err, `flag "bits": SetAllowPrefixes only applies to integer flags, not []bool`)
+	_, err = NewFloat32("scale").SetAllowPrefixes(true).Register(fs)
+	assert.EqualError(t, err, `flag "scale": SetAllowPrefixes only applies to integer flags, not float32`)
+	_, err = NewFloat32Slice("scales").SetAllowPrefixes(true).Register(fs)
+	assert.EqualError(t, err, `flag "scales": SetAllowPrefixes only applies to integer flags, not []float32`)
+	assert.Empty(t, fs.flags)
+
+	_, err = NewIntSlice("ids").SetAllowPrefixes(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewUint8Slice("bytes").SetAllowPrefixes(true).Register(fs)
+	assert.NoError(t, err)
+}
+
+func Test_IntPrefixes_CommandDefault(t *testing.T) {
+	fs := NewCmd("test").SetAllowIntPrefixes(true)
+
+	perms, err := NewUint32("perms").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	decimal, err := NewInt("decimal").SetAllowPrefixes(false).SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	sub := NewCmd("sub")
+	ids, err := NewIntSlice("ids").SetSeparator(",").Register(sub)
+	assert.NoError(t, err)
+	masks, err := NewUint8Slice("masks").SetOptional(true).SetFlagOnly(true).Register(sub)
+	assert.NoError(t, err)
+	_, err = fs.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--perms", "0644"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, uint32(0o644), *perms)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--perms", "1", "--decimal", "0b1"})
+	assert.EqualError(t, parseErr, "invalid integer value for decimal: 0b1")
+	assert.Equal(t, 0, *decimal)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--perms", "1", "sub", "0x10,0b11,1_0", "--masks", "0xFF"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []int{16, 3, 10}, *ids)
+	assert.Equal(t, []uint8{255}, *masks)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--perms", "1", "sub", "1", "--masks", "0x100"})
+	assert.EqualError(t, parseErr, "integer overflow for masks: 0x100 (value exceeds uint8 range)")
+}
+
+func Test_IntPrefixes_GlobalFlagFollowsParsingCommand(t *testing.T) {
+	root := NewCmd("app").SetAllowIntPrefixes(true)
+	mask, err := NewUint8("mask").Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+	sub := NewCmd("sub").SetAllowIntPrefixes(false)
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	// The shared flag uses the default of the command whose args it's in
+	for range 2 {
+		root.ResetParseState()
+		assert.NoError(t, root.ParseOrError([]string{"--mask", "0x10", "sub"}))
+		assert.Equal(t, uint8(16), *mask)
+
+		root.ResetParseState()
+		assert.EqualError(t, root.ParseOrError([]string{"sub", "--mask", "0x10"}), "invalid uint8 value for mask: 0x10")
+	}
+}
+
+func Test_ByteSize_Parses(t *testing.T) {
+	tests := map[string]int64{
+		"512":    512,
+		"512B":   512,
+		"512K":   512 * KiB,
+		"512k":   512 * KiB,
+		"1.5GiB": 3 * GiB / 2,
+		"10MB":   10 * MB,
+		"2 TiB":  2 * TiB,
+		"1.1K":   1126,
+	}
+	for input, expected := range tests {
+		fs := NewCmd("test")
+		size, err := NewByteSize("size").Register(fs)
+		assert.NoError(t, err)
+
+		parseErr := fs.ParseOrError([]string{input})
+		assert.Nil(t, parseErr, input)
+		assert.Equal(t, expected, *size, input)
+	}
+}
+
+func Test_ByteSize_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"10XB", "invalid byte size value for size: 10XB (expected e.g. 512K, 1.5GiB, 10MB)"},
+		{"-1K", "invalid byte size value for size: -1K (expected e.g. 512K, 1.5GiB, 10MB)"},
+		{"K", "invalid byte size value for size: K (expected e.g. 512K, 1.5GiB, 10MB)"},
+		{"9000PiB", "byte size overflow for size: 9000PiB (value exceeds int64 range)"},
+		{"2K", "'size' value 2KiB is < minimum 4KiB"},
+		{"2GB", "'size' value 2GB is > maximum 1GiB"},
+	}
+	for _, tt := range tests {
+		fs := NewCmd("test")
+		_, err := NewByteSize("size").SetMin(4*KiB, true).SetMax(GiB, true).SetFlagOnly(true).Register(fs)
+		assert.NoError(t, err)
+
+		parseErr := fs.ParseOrError([]string{"--size", tt.input})
+		assert.EqualError(t, parseErr, tt.expected, tt.input)
+	}
+
+	fs := NewCmd("test")
+	_, err := NewByteSize("size").SetDefault(KiB).SetMin(4*KiB, true).Register(fs)
+	assert.EqualError(t, err, `invalid default value for flag "size": value 1KiB is < minimum 4KiB`)
+}
+
+func Test_ByteSize_Format(t *testing.T) {
+	assert.Equal(t, "0B", formatByteSize(0))
+	assert.Equal(t, "512B", formatByteSize(512))
+	assert.Equal(t, "512KiB", formatByteSize(512*KiB))
+	assert.Equal(t, "10MB", formatByteSize(10*MB))
+	assert.Equal(t, "1.5GiB", formatByteSize(3*GiB/2))
+	assert.Equal(t, "1.21KiB", formatByteSize(1234))
+}
+
+func Test_Map_RepeatedAndSeparated(t *testing.T) {
+	fs := NewCmd("test")
+
+	labels, err := NewStringMap("label").SetShort("l").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	limits, err := NewIntMap("limit").SetDefault(map[string]int{"cpu": 1}).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--label", "env=prod", "-l", "team=core,tier=web=1", "--label=empty="})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core", "tier": "web=1", "empty": ""}, *labels)
+	assert.Equal(t, map[string]int{"cpu": 1}, *limits)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--label", "a=b", "--limit", "mem=512", "--limit", "mem=1024"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, map[string]int{"mem": 1024}, *limits)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--label", "novalue"})
+	assert.EqualError(t, parseErr, "invalid entry for label: novalue (expected key=value)")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--label", "a=b", "--limit", "mem=lots"})
+	assert.EqualError(t, parseErr, "invalid int value for limit key mem: lots")
+}
+
+func Test_Map_KeyConstraintsAndDuplicates(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewStringMap("label").
+		SetKeyRegexConstraint(regexp.MustCompile(`^[a-z]+$`)).
+		SetDuplicateKeys(DuplicateKeysError).
+		SetOptional(true).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+	_, err = NewStringMap("env").
+		SetKeyEnumConstraint([]string{"HOME", "PATH"}).
+		SetOptional(true).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--label", "Env=prod"})
+	assert.EqualError(t, parseErr, "Invalid 'label' key: Env (must match regex: ^[a-z]+$)")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--env", "USER=me"})
+	assert.EqualError(t, parseErr, "Invalid 'env' key: USER (valid keys: HOME, PATH)")
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--label", "env=prod", "--label", "env=dev"})
+	assert.EqualError(t, parseErr, `duplicate key "env" for label`)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--label", "env=prod,env=dev"})
+	assert.EqualError(t, parseErr, `duplicate key "env" for label`)
+}
+
+func Test_Map_EnvAndConfig(t *testing.T) {
+	t.Setenv("APP_LABELS", "env=prod,team=core")
+	fs := NewCmd("test")
+
+	labels, err := NewStringMap("labels").SetEnv("APP_LABELS").Register(fs)
+	assert.NoError(t, err)
+	limits, err := NewIntMap("limits").SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{}, WithConfigValues(map[string]any{
+		"limits": map[string]any{"cpu": float64(2), "mem": "512"},
+	}))
+	assert.Nil(t, parseErr)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, *labels)
+	assert.Equal(t, map[string]int{"cpu": 2, "mem": 512}, *limits)
+}
+
+func Test_Count_Occurrences(t *testing.T) {
+	fs := NewCmd("test")
+
+	verbose, err := NewCount("verbose").SetShort("v").Register(fs)
+	assert.NoError(t, err)
+	force, err := NewBool("force").SetShort("f").Register(fs)
+	assert.NoError(t, err)
+	path, err := NewString("path").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"-vvf", "file.txt", "--verbose", "-v"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 4, *verbose)
+	assert.True(t, *force)
+	assert.Equal(t, "file.txt", *path)
+
+	parseErr = fs.ParseOrError([]string{"file.txt"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 0, *verbose)
+}
+
+func Test_Count_ExplicitValueAndDefault(t *testing.T) {
+	fs := NewCmd("test")
+
+	verbose, err := NewCount("verbose").SetShort("v").SetDefault(1).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 1, *verbose)
+
+	// Occurrences count from zero, not from the default
+	parseErr = fs.ParseOrError([]string{"-vv"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 2, *verbose)
+
+	parseErr = fs.ParseOrError([]string{"--verbose=3"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 3, *verbose)
+
+	parseErr = fs.ParseOrError([]string{"-v=0"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 0, *verbose)
+
+	parseErr = fs.ParseOrError([]string{"--verbose=-1"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "invalid count value for verbose: -1 (expected a non-negative integer)", parseErr.Error())
+}
+
+func Test_Count_Max(t *testing.T) {
+	fs := NewCmd("test")
+
+	verbose, err := NewCount("verbose").SetShort("v").SetMax(2).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"-vv"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 2, *verbose)
+
+	parseErr = fs.ParseOrError([]string{"-vvv"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "'verbose' value 3 is > maximum 2", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--verbose=5"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "'verbose' value 5 is > maximum 2", parseErr.Error())
+
+	_, err = NewCount("quiet").SetDefault(3).SetMax(2).Register(NewCmd("test"))
+	assert.Error(t, err)
+	assert.Equal(t, `invalid default value for flag "quiet": value 3 is > maximum 2`, err.Error())
+}
+
+func Test_Count_Requires(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewCount("verbose").SetShort("v").SetRequires([]string{"log"}).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("log").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	// An uncounted flag doesn't trigger its requires constraint
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{"-v"})
+	assert.Error(t, parseErr)
+}
+
+func Test_Negatable_SetsFalse(t *testing.T) {
+	fs := NewCmd("test")
+
+	color, err := NewBool("color").SetDefault(true).SetNegatable(true).Register(fs)
+	assert.NoError(t, err)
+	cache, err := NewBool("cache").SetDefault(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.True(t, *color)
+	assert.False(t, fs.Configured("color"))
+
+	parseErr = fs.ParseOrError([]string{"--no-color"})
+	assert.Nil(t, parseErr)
+	assert.False(t, *color)
+	assert.True(t, fs.Configured("color"))
+
+	parseErr = fs.ParseOrError([]string{"--no-color=true"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "flag --no-color does not take a value", parseErr.Error())
+
+	// Flags aren't negatable unless enabled
+	parseErr = fs.ParseOrError([]string{"--no-cache"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "unknown flag: --no-cache", parseErr.Error())
+	assert.True(t, *cache)
+}
+
+func Test_Negatable_CommandDefault(t *testing.T) {
+	root := NewCmd("root").SetNegatableBools(true)
+	color, err := NewBool("color").SetDefault(true).Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+
+	sub := NewCmd("sub")
+	pager, err := NewBool("pager").SetDefault(true).Register(sub)
+	assert.NoError(t, err)
+	bell, err := NewBool("bell").SetDefault(true).SetNegatable(false).Register(sub)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"sub", "--no-pager", "--no-color"})
+	assert.Nil(t, parseErr)
+	assert.False(t, *pager)
+	assert.False(t, *color)
+
+	parseErr = root.ParseOrError([]string{"sub", "--no-bell"})
+	assert.Error(t, parseErr)
+	assert.True(t, *bell)
+}
+
+func Test_NoOptDefault_String(t *testing.T) {
+	fs := NewCmd("test")
+
+	color, err := NewString("color").
+		SetShort("c").
+		SetDefault("auto").
+		SetNoOptDefault("always").
+		SetEnumConstraint([]string{"auto", "always", "never"}).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+	file, err := NewString("file").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"in.txt"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "auto", *color)
+
+	// The following token is never consumed as the value
+	parseErr = fs.ParseOrError([]string{"--color", "in.txt"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "always", *color)
+	assert.Equal(t, "in.txt", *file)
+
+	parseErr = fs.ParseOrError([]string{"--color=never", "in.txt"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "never", *color)
+
+	parseErr = fs.ParseOrError([]string{"-c", "in.txt"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "always", *color)
+	assert.Equal(t, "in.txt", *file)
+
+	parseErr = fs.ParseOrError([]string{"-c=never", "in.txt"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "never", *color)
+
+	parseErr = fs.ParseOrError([]string{"--color=sometimes", "in.txt"})
+	assert.Error(t, parseErr)
+}
+
+func Test_NoOptDefault_Int(t *testing.T) {
+	fs := NewCmd("test")
+
+	level, err := NewInt("level").SetShort("l").SetOptional(true).SetNoOptDefault(5).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	count, err := NewInt("count").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--level", "3"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 5, *level)
+	assert.Equal(t, 3, *count)
+
+	parseErr = fs.ParseOrError([]string{"--level=9", "3"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 9, *level)
+
+	parseErr = fs.ParseOrError([]string{"-l", "3"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 5, *level)
+	assert.Equal(t, 3, *count)
+
+	_, err = NewInt("bad").SetMax(3, true).SetNoOptDefault(5).Register(NewCmd("test"))
+	assert.Error(t, err)
+	assert.Equal(t, `invalid no-opt default value for flag "bad": value 5 is > maximum 3`, err.Error())
+}
+
+func Test_FlagAliases(t *testing.T) {
+	fs := NewCmd("test")
+
+	path, err := NewString("path").SetShort("p").SetAliases("dir", "directory").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	force, err := NewBool("force").SetAliases("yes").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--dir", "a"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "a", *path)
+	assert.True(t, fs.Configured("path"))
+	assert.True(t, fs.Configured("dir"))
+	assert.False(t, fs.Configured("yes"))
+
+	parseErr = fs.ParseOrError([]string{"--directory=b", "--yes"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "b", *path)
+	assert.True(t, *force)
+
+	_, err = NewString("other").SetAliases("dir").Register(fs)
+	assert.Error(t, err)
+	assert.Equal(t, `alias "dir" of flag "other" is already an alias of flag "path"`, err.Error())
+
+	_, err = NewString("force2").SetAliases("force").Register(fs)
+	assert.Error(t, err)
+	assert.Equal(t, `alias "force" of flag "force2" conflicts with flag "force"`, err.Error())
+
+	_, err = NewString("yes").Register(fs)
+	assert.Error(t, err)
+	assert.Equal(t, `flag "yes" conflicts with alias of flag "force"`, err.Error())
+}
+
+func Test_FlagAliases_GlobalFlags(t *testing.T) {
+	root := NewCmd("root")
+	_, err := NewBool("verbose").SetAliases("loud").Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+
+	// A rejected global flag leaves nothing behind
+	_, err = NewBool("quiet").SetAliases("loud").Register(root, WithGlobal(true))
+	assert.EqualError(t, err, `alias "loud" of flag "quiet" is already an alias of flag "verbose"`)
+	assert.Equal(t, []string{"verbose"}, root.globalFlags)
+	_, err = NewString("debug").SetShort("d").SetAliases("").SetOptional(true).Register(root, WithGlobal(true))
+	assert.EqualError(t, err, `flag "debug" has an empty alias`)
+	assert.Equal(t, []string{"verbose"}, root.globalFlags)
+	assert.NotContains(t, root.shortToName, "d")
+
+	// A subcommand's flag can't clash with an inherited global flag's aliases
+	sub := NewCmd("sub")
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+	_, err = NewBool("noisy").SetAliases("loud").Register(sub)
+	assert.EqualError(t, err, `alias "loud" of flag "noisy" is already an alias of flag "verbose"`)
+	_, err = NewBool("quiet").SetAliases("verbose").Register(sub)
+	assert.EqualError(t, err, `alias "verbose" of flag "quiet" conflicts with flag "verbose"`)
+
+	// Nor one registered before the subcommand is
+	other := NewCmd("other")
+	_, err = NewBool("quiet").SetAliases("verbose").Register(other)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(other)
+	assert.EqualError(t, err, `global flag "verbose" of command "root": flag "verbose" conflicts with alias of flag "quiet"`)
+	assert.NotContains(t, root.subCmds, "other")
+	assert.NotContains(t, other.flags, "verbose")
+
+	// Overriding a global flag by name is still allowed
+	third := NewCmd("third")
+	_, err = root.RegisterCmd(third)
+	assert.NoError(t, err)
+	_, err = NewBool("verbose").SetAliases("loud").Register(third)
+	assert.NoError(t, err)
+}
+
+func Test_FlagAliases_RelationalConstraints(t *testing.T) {
+	fs := NewCmd("test")
+
+	_, err := NewString("path").SetAliases("dir").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("recursive").SetRequires([]string{"dir"}).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("stdin").SetExcludes([]string{"dir"}).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--recursive", "--dir", "a"})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{"--recursive"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: 'recursive' requires 'dir', but 'dir' was not set", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--stdin", "--path", "a"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: 'stdin' excludes 'path', but 'path' was set", parseErr.Error())
+}
+
+func Test_SubCmdAliases(t *testing.T) {
+	root := NewCmd("root")
+	remove := NewCmd("remove").SetAliases("rm", "del")
+	target, err := NewString("target").Register(remove)
+	assert.NoError(t, err)
+	used, err := root.RegisterCmd(remove)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"rm", "file.txt"})
+	assert.Nil(t, parseErr)
+	assert.True(t, *used)
+	assert.Equal(t, "file.txt", *target)
+
+	_, err = root.RegisterCmd(NewCmd("delete").SetAliases("del"))
+	assert.Error(t, err)
+	assert.Equal(t, `command "del" conflicts with command "remove"`, err.Error())
+
+	_, err = root.RegisterCmd(NewCmd("rm"))
+	assert.Error(t, err)
+	assert.Equal(t, `command "rm" conflicts with command "remove"`, err.Error())
+}
+
+func Test_Deprecated_WarnsOnStderr(t *testing.T) {
+	var stderr bytes.Buffer
+	originalStderr := stderrWriter
+	SetStderrWriter(&stderr)
+	defer SetStderrWriter(originalStderr)
+
+	fs := NewCmd("test")
+	out, err := NewString("out").SetDeprecated("use --output instead").SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+	output, err := NewString("output").SetDeprecatedAlias("dest", "use --output instead").SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	assert.Empty(t, stderr.String())
+
+	parseErr = fs.ParseOrError([]string{"--out", "a", "--dest", "b"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "a", *out)
+	assert.Equal(t, "b", *output)
+	assert.Equal(t, "Warning: flag --out is deprecated: use --output instead\n"+
+		"Warning: flag --dest is deprecated: use --output instead\n", stderr.String())
+}
+
+func Test_Deprecated_Hook(t *testing.T) {
+	root := NewCmd("root")
+	var warnings []string
+	root.SetParseHooks(&ParseHooks{
+		Deprecated: func(cmd *Cmd, warning string) {
+			warnings = append(warnings, cmd.name+": "+warning)
+		},
+	})
+	remove := NewCmd("remove").SetAliases("rm").SetDeprecatedAlias("del", "use 'remove' instead")
+	_, err := NewBool("force").SetDeprecated("it has no effect").Register(remove)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(remove)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("purge").SetDeprecated("use 'remove' instead"))
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"rm"})
+	assert.Nil(t, parseErr)
+	assert.Empty(t, warnings)
+
+	parseErr = root.ParseOrError([]string{"del", "--force"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []string{
+		`root: command "del" is deprecated: use 'remove' instead`,
+		"remove: flag --force is deprecated: it has no effect",
+	}, warnings)
+
+	warnings = nil
+	parseErr = root.ParseOrError([]string{"purge"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, []string{`root: command "purge" is deprecated: use 'remove' instead`}, warnings)
+}
+
+func Test_Deprecated_AsErrors(t *testing.T) {
+	root := NewCmd("root")
+	sub := NewCmd("sub")
+	_, err := NewInt("retries").SetDeprecated("retries are automatic").SetOptional(true).Register(sub)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"sub", "--retries", "3"}, WithDeprecationErrors(true))
+	assert.Error(t, parseErr)
+	assert.Equal(t, "flag --retries is deprecated: retries are automatic", parseErr.Error())
+}
+
+func Test_Suggestions(t *testing.T) {
+	root := NewCmd("root")
+	_, err := NewBool("verbose").SetAliases("loud").Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("output").SetOptional(true).SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("outpost").SetOptional(true).SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewBool("secret").SetHidden(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("color").SetEnumConstraint([]string{"red", "green", "blue"}).SetOptional(true).Register(root)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("status").SetAliases("st"))
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"--verbos"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown flag: --verbos, did you mean --verbose?")
+
+	parseErr = root.ParseOrError([]string{"--outpst", "x"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown flag: --outpst, did you mean --outpost or --output?")
+
+	parseErr = root.ParseOrError([]string{"--lod"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown flag: --lod, did you mean --loud?")
+
+	// Hidden flags aren't suggested
+	parseErr = root.ParseOrError([]string{"--secrte"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown flag: --secrte")
+	assert.NotContains(t, parseErr.Error(), "did you mean")
+
+	parseErr = root.ParseOrError([]string{"--color", "bleu"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "Invalid 'color' value: bleu (valid values: red, green, blue), did you mean blue?")
+
+	parseErr = root.ParseOrError([]string{"stauts"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown command: stauts, did you mean status?")
+}
+
+func Test_Suggestions_Configurable(t *testing.T) {
+	root := NewCmd("root").SetSuggestions(false)
+	sub := NewCmd("sub")
+	_, err := NewBool("verbose").Register(sub)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"sub", "--verbos"})
+	assert.Error(t, parseErr)
+	assert.NotContains(t, parseErr.Error(), "did you mean")
+
+	sub.SetSuggestions(true)
+	parseErr = root.ParseOrError([]string{"sub", "--verbos"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "did you mean --verbose?")
+
+	parseErr = root.ParseOrError([]string{"sub", "--vrbs"})
+	assert.Error(t, parseErr)
+	assert.NotContains(t, parseErr.Error(), "did you mean")
+
+	sub.SetSuggestionDistance(3)
+	parseErr = root.ParseOrError([]string{"sub", "--vrbs"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "did you mean --verbose?")
+}
+
+func Test_Abbreviations(t *testing.T) {
+	fs := NewCmd("test")
+	verbose, err := NewBool("verbose").Register(fs)
+	assert.NoError(t, err)
+	version, err := NewBool("version").Register(fs)
+	assert.NoError(t, err)
+	path, err := NewString("path").SetAliases("dir", "directory").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("secret").SetHidden(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--verb", "--vers", "--di=a"}, WithAllowAbbreviations(true))
+	assert.Nil(t, parseErr)
+	assert.True(t, *verbose)
+	assert.True(t, *version)
+	assert.Equal(t, "a", *path)
+	assert.True(t, fs.Configured("verbose"))
+
+	parseErr = fs.ParseOrError([]string{"--ver"}, WithAllowAbbreviations(true))
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "ambiguous flag: --ver, could be --verbose or --version")
+
+	// Hidden flags can't be abbreviated
+	parseErr = fs.ParseOrError([]string{"--sec"}, WithAllowAbbreviations(true))
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown flag: --sec")
+
+	// Off by default
+	parseErr = fs.ParseOrError([]string{"--verb"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "unknown flag: --verb")
+}
+
+func Test_Abbreviations_ShadowedGlobalFlag(t *testing.T) {
+	root := NewCmd("root")
+	globalVerbose, err := NewBool("verbose").SetShort("v").Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+	// Takes the global flag's name, leaving it reachable only by its short
+	localVerbose, err := NewBool("verbose").Register(root)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"--verb"}, WithAllowAbbreviations(true))
+	assert.Nil(t, parseErr)
+	assert.True(t, *localVerbose)
+	assert.False(t, *globalVerbose)
+
+	parseErr = root.ParseOrError([]string{"-v"}, WithAllowAbbreviations(true))
+	assert.Nil(t, parseErr)
+	assert.True(t, *globalVerbose)
+}
+
+func Test_SubCmdAbbreviations(t *testing.T) {
+	root := NewCmd("root")
+	status, err := root.RegisterCmd(NewCmd("status"))
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("start"))
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("remove").SetAliases("rm"))
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"stat"}, WithAllowSubCmdAbbreviations(true))
+	assert.Nil(t, parseErr)
+	assert.True(t, *status)
+
+	parseErr = root.ParseOrError([]string{"st"}, WithAllowSubCmdAbbreviations(true))
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "ambiguous command: st, could be start or status")
+
+	// Flag abbreviations don't enable subcommand abbreviations
+	parseErr = root.ParseOrError([]string{"stat"}, WithAllowAbbreviations(true))
+	assert.Error(t, parseErr)
+}
+
+func Test_FlagGroups_ExactlyOne(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewString("file").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("url").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("stdin").Register(fs)
+	assert.NoError(t, err)
+	fs.AddFlagGroup(ExactlyOne("file", "url", "stdin"))
+
+	// Grouped flags aren't individually required
+	parseErr := fs.ParseOrError([]string{"--url", "x"})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{"--stdin"})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: exactly one of 'file', 'url' or 'stdin' must be set", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--file", "a", "--stdin"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: exactly one of 'file', 'url' or 'stdin' must be set, but 'file' and 'stdin' were set", parseErr.Error())
+}
+
+func Test_FlagGroups_OtherKinds(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewBool("json").Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("yaml").Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("user").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("password").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewStringSlice("tag").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("label").SetDefault("x").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	fs.AddFlagGroup(AtMostOne("json", "yaml")).
+		AddFlagGroup(AllOrNone("user", "password")).
+		AddFlagGroup(AtLeastOne("tag", "label"))
+
+	parseErr := fs.ParseOrError([]string{"--tag", "a", "--label", "b", "--json"})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{"--tag", "a", "--json", "--yaml"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: at most one of 'json' or 'yaml' may be set, but 'json' and 'yaml' were set", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--tag", "a", "--user", "bob"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: all or none of 'user' and 'password' must be set, but 'password' was not set", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--tag", "a", "--user", "bob", "--password", "pw"})
+	assert.Nil(t, parseErr)
+
+	// A default doesn't count towards a group
+	parseErr = fs.ParseOrError([]string{})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: at least one of 'tag' or 'label' must be set", parseErr.Error())
+}
+
+func Test_FlagGroups_UndefinedFlag(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewBool("json").Register(fs)
+	assert.NoError(t, err)
+	fs.AddFlagGroup(ExactlyOne("json", "xml"))
+
+	parseErr := fs.ParseOrError([]string{"--json"})
+	assert.Error(t, parseErr)
+	var progErr *ProgrammingError
+	assert.True(t, errors.As(parseErr, &progErr))
+	assert.Contains(t, parseErr.Error(), "Undefined flag 'xml'")
+}
+
+func Test_RequiredIf(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewString("mode").SetEnumConstraint([]string{"local", "remote"}).SetDefault("local").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	host, err := NewString("host").SetRequiredIf("mode", "remote").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	// Not required while the condition doesn't hold, including through the default
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{"--mode", "remote"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: 'host' is required when 'mode' is 'remote'", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--mode", "remote", "--host", "example.com"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "example.com", *host)
+}
+
+func Test_RequiredWhen(t *testing.T) {
+	fs := NewCmd("test")
+	replicas, err := NewInt("replicas").SetDefault(1).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("balancer").
+		SetRequiredWhen("more than one replica", func(cmd *Cmd) bool { return *replicas > 1 }).
+		SetFlagOnly(true).
+		Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--replicas", "1"})
+	assert.Nil(t, parseErr)
+
+	parseErr = fs.ParseOrError([]string{"--replicas", "3"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: 'balancer' is required when more than one replica", parseErr.Error())
+}
+
+func Test_RequiredIf_LeavesOptionalAlone(t *testing.T) {
+	host := NewString("host").SetOptional(false).SetRequiredIf("mode", "remote")
+	assert.False(t, host.Optional)
+	port := NewInt("port").SetOptional(true).SetRequiredWhen("always", func(*Cmd) bool { return true })
+	assert.True(t, port.Optional)
+
+	fs := NewCmd("test")
+	_, err := NewString("mode").SetDefault("local").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = host.Register(fs)
+	assert.NoError(t, err)
+
+	// Only the condition decides whether it's required
+	parseErr := fs.ParseOrError([]string{})
+	assert.Nil(t, parseErr)
+	parseErr = fs.ParseOrError([]string{"--mode", "remote"})
+	assert.EqualError(t, parseErr, "Invalid args: 'host' is required when 'mode' is 'remote'")
+	assert.Contains(t, fs.GenerateShortUsage(), "[host]")
+}
+
+func Test_ExcludesIf(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewString("protocol").SetDefault("tcp").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewInt("port").SetExcludesIf([]string{"socket"}, "protocol", "tcp").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("socket").SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--port", "80", "--socket", "/tmp/s"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid args: 'port' excludes 'socket' when 'protocol' is 'tcp', but 'socket' was set", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--protocol", "unix", "--port", "80", "--socket", "/tmp/s"})
+	assert.Nil(t, parseErr)
+}
+
+func Test_ConditionalConstraints_UndefinedFlag(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewString("host").SetRequiredIf("mode", "remote").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{})
+	assert.Error(t, parseErr)
+	var progErr *ProgrammingError
+	assert.True(t, errors.As(parseErr, &progErr))
+	assert.Contains(t, parseErr.Error(), "Undefined flag 'mode'")
+}
+
+func Test_Validator(t *testing.T) {
+	even := func(v int) error {
+		if v%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	}
+
+	fs := NewCmd("test")
+	count, err := NewInt("count").SetMin(0, true).SetValidator(even).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--count", "4"})
+	assert.Nil(t, parseErr)
+	assert.Equal(t, 4, *count)
+
+	parseErr = fs.ParseOrError([]string{"--count", "3"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid 'count' value: 3 (must be even)", parseErr.Error())
+	var progErr *ProgrammingError
+	assert.False(t, errors.As(parseErr, &progErr))
+
+	// Built-in constraints are checked first
+	parseErr = fs.ParseOrError([]string{"--count", "-1"})
+	assert.Error(t, parseErr)
+	assert.Contains(t, parseErr.Error(), "'count' value -1 is < minimum 0")
+}
+
+func Test_Validator_SliceAndValueFlags(t *testing.T) {
+	lower := func(s string) error {
+		if s != strings.ToLower(s) {
+			return errors.New("must be lowercase")
+		}
+		return nil
+	}
+	short := func(d time.Duration) error {
+		if d > time.Minute {
+			return errors.New("at most 1m")
+		}
+		return nil
+	}
+
+	fs := NewCmd("test")
+	_, err := NewStringSlice("tags").SetSeparator(",").SetValidator(lower).SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewDuration("wait").SetValidator(short).SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewStringMap("label").SetValidator(lower).SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("force").SetValidator(func(b bool) error {
+		if b {
+			return errors.New("disabled")
+		}
+		return nil
+	}).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--tags", "a,B"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid 'tags' value: B (must be lowercase)", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--wait", "2m"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid 'wait' value: 2m (at most 1m)", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--label", "env=Prod"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid 'label' value: env=Prod (must be lowercase)", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"--force"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "Invalid 'force' value: true (disabled)", parseErr.Error())
+}
+
+func Test_Validator_SliceUnchangedOnFailure(t *testing.T) {
+	small := func(v int) error {
+		if v >= 10 {
+			return errors.New("must be < 10")
+		}
+		return nil
+	}
+
+	fs := NewCmd("test")
+	ids, err := NewIntSlice("ids").SetSeparator(",").SetDefault([]int{1, 2}).SetValidator(small).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	waits, err := NewDurationSlice("waits").SetSeparator(",").SetDefault([]time.Duration{time.Second}).SetValidator(func(d time.Duration) error {
+		if d > time.Minute {
+			return errors.New("at most 1m")
+		}
+		return nil
+	}).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	// A failing element leaves the default in place
+	parseErr := fs.ParseOrError([]string{"--ids", "3,40"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, ConstraintViolation, parseErr.(*ParseError).Kind)
+	assert.Equal(t, []int{1, 2}, *ids)
+
+	parseErr = fs.ParseOrError([]string{"--waits", "2s,2m"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, []time.Duration{time.Second}, *waits)
+
+	// And doesn't append the elements before it to earlier values
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--ids", "3", "--ids", "4,50"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, []int{3}, *ids)
+
+	fs.ResetParseState()
+	parseErr = fs.ParseOrError([]string{"--ids", "12"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, []int{1, 2}, *ids)
+}
+
+func Test_Validator_Default(t *testing.T) {
+	positive := func(v float64) error {
+		if v <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}
+
+	fs := NewCmd("test")
+	_, err := NewFloat64("ratio").SetDefault(-1).SetValidator(positive).Register(fs)
+	assert.EqualError(t, err, `invalid default value for flag "ratio": must be positive`)
+
+	_, err = NewIntSlice("ports").SetDefault([]int{80, 0}).SetValidator(func(v int) error {
+		if v == 0 {
+			return errors.New("port cannot be 0")
+		}
+		return nil
+	}).Register(fs)
+	assert.EqualError(t, err, `invalid default value for flag "ports": port cannot be 0`)
+
+	_, err = NewString("color").SetNoOptDefault("Auto").SetValidator(func(s string) error {
+		if s != strings.ToLower(s) {
+			return errors.New("must be lowercase")
+		}
+		return nil
+	}).SetOptional(true).Register(fs)
+	assert.EqualError(t, err, `invalid no-opt default value for flag "color": must be lowercase`)
+}
+
+func Test_CollectErrors(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewString("name").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewInt("count").SetMax(10, true).SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("level").SetEnumConstraint([]string{"low", "high"}).SetOptional(true).SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("quiet").SetExcludes([]string{"loud"}).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewBool("loud").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError(
+		[]string{"--bogus", "--count", "11", "--level", "mid", "--quiet", "--loud"},
+		WithCollectErrors(true),
+	)
+	assert.Error(t, parseErr)
+	var errs ParseErrors
+	assert.True(t, errors.As(parseErr, &errs))
+	assert.Equal(t, []string{
+		"unknown flag: --bogus",
+		"'count' value 11 is > maximum 10",
+		"Invalid 'level' value: mid (valid values: low, high)",
+		"Invalid args: 'quiet' excludes 'loud', but 'loud' was set",
+		"Missing required arguments: [name]",
+	}, strings.Split(parseErr.Error(), "\n"))
+	assert.Len(t, errs, 5)
+}
+
+func Test_CollectErrors_Subcommand(t *testing.T) {
+	root := NewCmd("root")
+	_, err := NewBool("verbose").Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+	sub := NewCmd("sub")
+	_, err = NewInt("port").SetFlagOnly(true).Register(sub)
+	assert.NoError(t, err)
+	_, err = NewString("host").SetFlagOnly(true).Register(sub)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"--verbos", "sub", "--port", "x"}, WithCollectErrors(true))
+	assert.Error(t, parseErr)
+	assert.Equal(t, "unknown flag: --verbos, did you mean --verbose?\n"+
+		"invalid integer value for port: x\n"+
+		"Missing required arguments: [host]", parseErr.Error())
+}
+
+func Test_CollectErrors_Off(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewInt("count").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--bogus", "--count", "x"})
+	assert.Error(t, parseErr)
+	assert.Equal(t, "unknown flag: --bogus", parseErr.Error())
+	var errs ParseErrors
+	assert.False(t, errors.As(parseErr, &errs))
+
+	// Programming errors still end the parse immediately
+	_, err = NewString("host").SetRequires([]string{"missing"}).SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+	parseErr = fs.ParseOrError([]string{"--bogus"}, WithCollectErrors(true))
+	var progErr *ProgrammingError
+	assert.True(t, errors.As(parseErr, &progErr))
+}
+
+func Test_CollectErrors_ParseOrExit(t *testing.T) {
+	cleanup, exitCode, _, stderr := mockExit(t)
+	defer cleanup()
+
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		fs := NewCmd("test")
+		NewInt("count").SetFlagOnly(true).Register(fs)
+		fs.ParseOrExit([]string{"--bogus", "--count", "x"}, WithCollectErrors(true))
+	})
+
+	assert.Equal(t, 1, *exitCode)
+	assert.True(t, strings.HasPrefix(stderr.String(), "unknown flag: --bogus\ninvalid integer value for count: x\n\nUsage:"))
+}
+
+func Test_ParseError(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewInt("count").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+	_, err = NewString("name").Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"x", "--bogus"})
+	var pe *ParseError
+	assert.True(t, errors.As(parseErr, &pe))
+	assert.Equal(t, UnknownFlag, pe.Kind)
+	assert.Equal(t, "bogus", pe.Flag)
+	assert.Equal(t, 1, pe.ArgIndex)
+	assert.Equal(t, "test", pe.Cmd)
+	assert.Equal(t, "unknown flag: --bogus", parseErr.Error())
+
+	parseErr = fs.ParseOrError([]string{"x", "--count", "abc"})
+	assert.True(t, errors.As(parseErr, &pe))
+	assert.Equal(t, InvalidValue, pe.Kind)
+	assert.Equal(t, "count", pe.Flag)
+	assert.Equal(t, "abc", pe.Value)
+	assert.Equal(t, 1, pe.ArgIndex)
+
+	parseErr = fs.ParseOrError([]string{"--count", "1"})
+	assert.True(t, errors.As(parseErr, &pe))
+	assert.Equal(t, MissingRequired, pe.Kind)
+	assert.Equal(t, "name", pe.Flag)
+	assert.Equal(t, -1, pe.ArgIndex)
+	assert.Equal(t, "missing required", pe.Kind.String())
+}
+
+func Test_ParseError_Subcommand(t *testing.T) {
+	root := NewCmd("root")
+	sub := NewCmd("sub")
+	_, err := NewInt("port").SetFlagOnly(true).Register(sub)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	parseErr := root.ParseOrError([]string{"sub", "--port", "x"})
+	var pe *ParseError
+	assert.True(t, errors.As(parseErr, &pe))
+	assert.Equal(t, InvalidValue, pe.Kind)
+	assert.Equal(t, "sub", pe.Cmd)
+	assert.Equal(t, 1, pe.ArgIndex)
+}
+
+func Test_ParseError_Validator(t *testing.T) {
+	errOdd := errors.New("must be even")
+	fs := NewCmd("test")
+	_, err := NewInt("n").SetValidator(func(v int) error {
+		if v%2 != 0 {
+			return errOdd
+		}
+		return nil
+	}).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"3"})
+	var pe *ParseError
+	assert.True(t, errors.As(parseErr, &pe))
+	assert.Equal(t, ConstraintViolation, pe.Kind)
+	assert.Equal(t, "3", pe.Value)
+	assert.Equal(t, 0, pe.ArgIndex)
+	assert.True(t, errors.Is(parseErr, errOdd))
+}
+
+func Test_ParseError_Collected(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewInt("count").SetFlagOnly(true).Register(fs)
+	assert.NoError(t, err)
+
+	parseErr := fs.ParseOrError([]string{"--bogus", "--count", "x"}, WithCollectErrors(true))
+	var errs ParseErrors
+	assert.True(t, errors.As(parseErr, &errs))
+	assert.Len(t, errs, 2)
+	kinds := []ParseErrorKind{UnknownFlag, InvalidValue}
+	indexes := []int{0, 1}
+	for i, err := range errs {
+		var pe *ParseError
+		assert.True(t, errors.As(err, &pe))
+		assert.Equal(t, kinds[i], pe.Kind)
+		assert.Equal(t, indexes[i], pe.ArgIndex)
+		assert.Equal(t, "test", pe.Cmd)
+	}
+}
+
+func Test_Execute(t *testing.T) {
+	root := NewCmd("root")
+	var ran []string
+	root.SetRun(func(ctx context.Context, cmd *Cmd) error {
+		ran = append(ran, "root")
+		return nil
+	})
+	sub := NewCmd("sub")
+	port, err := NewInt("port").SetFlagOnly(true).SetDefault(80).Register(sub)
+	assert.NoError(t, err)
+	sub.SetRun(func(ctx context.Context, cmd *Cmd) error {
+		ran = append(ran, fmt.Sprintf("%s %d", cmd.name, *port))
+		return nil
+	})
+	_, err = root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	root.Execute(context.Background(), []string{"sub", "--port", "8080"})
+	assert.Equal(t, []string{"sub 8080"}, ran)
+
+	root.ResetParseState()
+	root.Execute(context.Background(), []string{})
+	assert.Equal(t, []string{"sub 8080", "root"}, ran)
+}
+
+func Test_Execute_Hooks(t *testing.T) {
+	var calls []string
+	hooks := func(name string) *ParseHooks {
+		return &ParseHooks{
+			PreRun: func(ctx context.Context, cmd *Cmd) error {
+				calls = append(calls, name+" pre "+cmd.name)
+				return nil
+			},
+			PostRun: func(ctx context.Context, cmd *Cmd) error {
+				calls = append(calls, name+" post "+cmd.name)
+				return nil
+			},
+		}
+	}
+	root := NewCmd("root").SetParseHooks(hooks("root"))
+	mid := NewCmd("mid").SetParseHooks(hooks("mid"))
+	leaf := NewCmd("leaf").SetParseHooks(hooks("leaf")).SetRun(func(ctx context.Context, cmd *Cmd) error {
+		calls = append(calls, "run")
+		return nil
+	})
+	_, err := mid.RegisterCmd(leaf)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(mid)
+	assert.NoError(t, err)
+
+	root.Execute(context.Background(), []string{"mid", "leaf"})
+	assert.Equal(t, []string{
+		"root pre leaf", "mid pre leaf", "leaf pre leaf",
+		"run",
+		"leaf post leaf", "mid post leaf", "root post leaf",
+	}, calls)
+}
+
+func Test_Execute_Errors(t *testing.T) {
+	cleanup, exitCode, _, stderr := mockExit(t)
+	defer cleanup()
+
+	root := NewCmd("root")
+	ranSub := false
+	root.SetParseHooks(&ParseHooks{
+		PreRun: func(ctx context.Context, cmd *Cmd) error {
+			if cmd.name == "locked" {
+				return NewExitError(3, errors.New("locked"))
+			}
+			return nil
+		},
+	})
+	fail := NewCmd("fail").SetRun(func(ctx context.Context, cmd *Cmd) error {
+		return errors.New("it broke")
+	})
+	quiet := NewCmd("quiet").SetRun(func(ctx context.Context, cmd *Cmd) error {
+		return NewExitError(2, nil)
+	})
+	locked := NewCmd("locked").SetRun(func(ctx context.Context, cmd *Cmd) error {
+		ranSub = true
+		return nil
+	})
+	for _, sub := range []*Cmd{fail, quiet, locked} {
+		_, err := root.RegisterCmd(sub)
+		assert.NoError(t, err)
+	}
+
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		root.Execute(context.Background(), []string{"fail"})
+	})
+	assert.Equal(t, 1, *exitCode)
+	assert.Equal(t, "it broke\n", stderr.String())
+
+	stderr.Reset()
+	root.ResetParseState()
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		root.Execute(context.Background(), []string{"quiet"})
+	})
+	assert.Equal(t, 2, *exitCode)
+	assert.Empty(t, stderr.String())
+
+	root.ResetParseState()
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		root.Execute(context.Background(), []string{"locked"})
+	})
+	assert.Equal(t, 3, *exitCode)
+	assert.Equal(t, "locked\n", stderr.String())
+	assert.False(t, ranSub)
+
+	// No handler on a command with subcommands prints its usage
+	stderr.Reset()
+	root.ResetParseState()
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		root.Execute(context.Background(), []string{})
+	})
+	assert.Equal(t, 1, *exitCode)
+	assert.Contains(t, stderr.String(), "Usage:")
+
+	// Parse errors exit as with ParseOrExit
+	stderr.Reset()
+	root.ResetParseState()
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		root.Execute(context.Background(), []string{"fail", "--bogus"})
+	})
+	assert.Equal(t, 1, *exitCode)
+	assert.Contains(t, stderr.String(), "unknown flag: --bogus")
+}
+
+func Test_ExecuteContext_HookContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "req-1")
+	var seen []any
+	root := NewCmd("root")
+	root.SetParseHooks(&ParseHooks{
+		PostParse: func(cmd *Cmd, err error) {
+			seen = append(seen, cmd.Context().Value(ctxKey{}))
+		},
+		PreRun: func(ctx context.Context, cmd *Cmd) error {
+			seen = append(seen, ctx.Value(ctxKey{}))
+			return nil
+		},
+	})
+	sub := NewCmd("sub").SetRun(func(ctx context.Context, cmd *Cmd) error {
+		seen = append(seen, ctx.Value(ctxKey{}), cmd.Context().Value(ctxKey{}))
+		return nil
+	})
+	_, err := root.RegisterCmd(sub)
+	assert.NoError(t, err)
+
+	root.ExecuteContext(ctx, []string{"sub"})
+	assert.Equal(t, []any{"req-1", "req-1", "req-1", "req-1"}, seen)
+
+	root.ResetParseState()
+	assert.Equal(t, context.Background(), root.Context())
+}
+
+func Test_ExecuteContext_Signal(t *testing.T) {
+	cleanup, exitCode, _, stderr := mockExit(t)
+	defer cleanup()
+
+	cmd := NewCmd("serve").SetRun(func(ctx context.Context, cmd *Cmd) error {
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		if err := p.Signal(os.Interrupt); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("context not cancelled")
+		}
+	})
+
+	assert.PanicsWithValue(t, "os.Exit called", func() {
+		cmd.ExecuteContext(context.Background(), []string{}, WithSignalHandling(true))
+	})
+	assert.Equal(t, 130, *exitCode)
+	assert.Empty(t, stderr.String())
+}
+
+func Test_Parse(t *testing.T) {
+	root := NewCmd("app")
+	verbose, err := NewBool("verbose").SetShort("v").Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+	remote := NewCmd("remote")
+	add := NewCmd("add")
+	name, err := NewString("name").Register(add)
+	assert.NoError(t, err)
+	port, err := NewInt("port").SetFlagOnly(true).SetOptional(true).SetDefault(22).Register(add)
+	assert.NoError(t, err)
+	tags, err := NewStringSlice("tag").SetFlagOnly(true).SetOptional(true).Register(add)
+	assert.NoError(t, err)
+	_, err = NewDuration("timeout").SetFlagOnly(true).SetOptional(true).Register(add)
+	assert.NoError(t, err)
+	_, err = remote.RegisterCmd(add)
+	assert.NoError(t, err)
+	remoteUsed, err := root.RegisterCmd(remote)
+	assert.NoError(t, err)
+
+	res, err := root.Parse([]string{"remote", "add", "origin", "-v", "--tag", "a", "--tag", "b", "--timeout", "5s", "--bogus"},
+		WithIgnoreUnknown(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "origin", Get[string](res, "name"))
+	assert.Equal(t, 22, Get[int](res, "port"))
+	assert.Equal(t, []string{"a", "b"}, Get[[]string](res, "tag"))
+	assert.Equal(t, 5*time.Second, Get[time.Duration](res, "timeout"))
+	assert.True(t, Get[bool](res, "verbose"))
+	assert.True(t, res.Configured("verbose"))
+	assert.False(t, res.Configured("port"))
+	assert.Equal(t, []string{"app", "remote", "add"}, res.InvokedPath())
+	assert.Equal(t, []string{"--bogus"}, res.UnknownArgs())
+
+	// The command and its registered pointers are untouched
+	assert.False(t, *verbose)
+	assert.Equal(t, "", *name)
+	assert.Equal(t, 0, *port)
+	assert.Nil(t, *tags)
+	assert.False(t, *remoteUsed)
+	assert.False(t, root.Configured("verbose"))
+
+	// A second parse doesn't affect the first result
+	res2, err := root.Parse([]string{"remote", "add", "upstream"})
+	assert.NoError(t, err)
+	assert.Equal(t, "upstream", Get[string](res2, "name"))
+	assert.Equal(t, "origin", Get[string](res, "name"))
+	assert.False(t, Get[bool](res2, "verbose"))
+
+	_, err = root.Parse([]string{"remote", "add"})
+	assert.Error(t, err)
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, MissingRequired, pe.Kind)
+}
+
+func Test_Parse_Get_Panics(t *testing.T) {
+	fs := NewCmd("test")
+	_, err := NewInt("port").SetOptional(true).Register(fs)
+	assert.NoError(t, err)
+	res, err := fs.Parse([]string{"80"})
+	assert.NoError(t, err)
+
+	assert.PanicsWithError(t, `flag "port" is not of type string`, func() {
+		Get[string](res, "port")
+	})
+	assert.PanicsWithError(t, `flag "host" is not defined on the invoked commands`, func() {
+		Get[string](res, "host")
+	})
+}
+
+func Test_Parse_Concurrent(t *testing.T) {
+	root := NewCmd("bot")
+	_, err := NewBool("dry-run").Register(root, WithGlobal(true))
+	assert.NoError(t, err)
+	deploy := NewCmd("deploy")
+	_, err = NewString("service").Register(deploy)
+	assert.NoError(t, err)
+	_, err = NewStringSlice("region").SetFlagOnly(true).SetOptional(true).Register(deploy)
+	assert.NoError(t, err)
+	_, err = NewCount("verbose").SetShort("v").Register(deploy)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(deploy)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service := fmt.Sprintf("svc-%d", i)
+			args := []string{"deploy", service, "--region", service}
+			for range i % 3 {
+				args = append(args, "-v")
+			}
+			if i%2 == 0 {
+				args = append(args, "--dry-run")
+			}
+			res, err := root.Parse(args)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, service, Get[string](res, "service"))
+			assert.Equal(t, []string{service}, Get[[]string](res, "region"))
+			assert.Equal(t, i%3, Get[int](res, "verbose"))
+			assert.Equal(t, i%2 == 0, Get[bool](res, "dry-run"))
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_Parse_Concurrent_WithColorEnv(t *testing.T) {
+	t.Setenv("RA_COLOR", "never")
+	root := NewCmd("bot")
+	_, err := NewString("service").SetUsage("Service to deploy").Register(root)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			res, err := root.Parse([]string{fmt.Sprintf("svc-%d", i)})
+			if assert.NoError(t, err) {
+				assert.Equal(t, fmt.Sprintf("svc-%d", i), Get[string](res, "service"))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Contains(t, root.GenerateLongUsage(), "Service to deploy")
+		}()
+	}
+	wg.Wait()
+}
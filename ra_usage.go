@@ -2,6 +2,7 @@ package ra
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -95,7 +96,7 @@ func (b *BaseFlag) isVisible(isLongHelp bool) bool {
 	if b.Hidden {
 		return false
 	}
-	if !isLongHelp && b.HiddenInShortHelp {
+	if !isLongHelp && (b.HiddenInShortHelp || b.Deprecated != "") {
 		return false
 	}
 	return true
@@ -107,7 +108,7 @@ func (c *Cmd) isVisible(isLongHelp bool) bool {
 	if c.hidden {
 		return false
 	}
-	if !isLongHelp && c.hiddenInShortHelp {
+	if !isLongHelp && (c.hiddenInShortHelp || c.deprecated != "") {
 		return false
 	}
 	return true
@@ -145,7 +146,7 @@ func (c *Cmd) generateCommandsSection(isLongHelp bool) string {
 		if !c.subCmds[name].isVisible(isLongHelp) {
 			continue
 		}
-		cmdWidth := len(name) + 2 // 2 for leading "  "
+		cmdWidth := len(c.subCmds[name].displayNames()) + 2 // 2 for leading "  "
 		if cmdWidth > maxWidth {
 			maxWidth = cmdWidth
 		}
@@ -160,12 +161,15 @@ func (c *Cmd) generateCommandsSection(isLongHelp bool) string {
 		if !subCmd.isVisible(isLongHelp) {
 			continue
 		}
-		cmdPart := fmt.Sprintf("  %s", name)
+		cmdPart := fmt.Sprintf("  %s", subCmd.displayNames())
 		sb.WriteString(cmdPart)
 
-		if subCmd.description != "" {
+		if subCmd.description != "" || subCmd.deprecated != "" {
 			// Only show first line of description in command list for better readability
 			firstLine := strings.Split(subCmd.description, "\n")[0]
+			if subCmd.deprecated != "" {
+				firstLine = strings.TrimSpace("(deprecated) " + firstLine)
+			}
 
 			// Calculate padding to align descriptions
 			padding := maxWidth - len(cmdPart)
@@ -181,6 +185,11 @@ func (c *Cmd) generateCommandsSection(isLongHelp bool) string {
 	return sb.String()
 }
 
+// displayNames renders a command's name followed by its aliases, e.g. "remove, rm".
+func (c *Cmd) displayNames() string {
+	return strings.Join(append([]string{c.name}, c.visibleAliases()...), ", ")
+}
+
 func (c *Cmd) separateScriptAndGlobalFlags() (scriptFlags, globalFlags []any) {
 	// Use a map to keep track of added flags to avoid duplicates
 	addedFlags := make(map[string]bool)
@@ -395,6 +404,13 @@ func (c *Cmd) generateSynopsis(isLongHelp bool) string {
 		sb.WriteString(" " + CyanS("[%s]", headers.SubcommandPlaceholder))
 	}
 
+	// Flag groups go just before [OPTIONS]
+	var options string
+	for _, group := range c.flagGroupsSynopsis(isLongHelp) {
+		options += " " + CyanS(group)
+	}
+	options += " " + CyanS("[OPTIONS]")
+
 	// First pass: collect positional-only flags
 	var positionalOnlyFlags []string
 	var nonPositionalFlags []string
@@ -406,8 +422,11 @@ func (c *Cmd) generateSynopsis(isLongHelp bool) string {
 			continue
 		}
 
-		if isBoolFlag(flag) {
-			continue // Bools never appear in synopsis
+		if takesNoValue(flag) {
+			continue // Bools and counts never appear in synopsis
+		}
+		if c.isFlagInGroup(name) {
+			continue // Shown with their group instead
 		}
 
 		if base.PositionalOnly {
@@ -439,8 +458,8 @@ func (c *Cmd) generateSynopsis(isLongHelp bool) string {
 			continue
 		}
 
-		if isBoolFlag(flag) || base.Optional {
-			continue // Bools and optional flags never appear in synopsis
+		if takesNoValue(flag) || isOptional(base) || c.isFlagInGroup(name) {
+			continue // Bools, counts, optional and grouped flags never appear individually
 		}
 
 		// Check if already added
@@ -490,6 +509,11 @@ func (c *Cmd) generateSynopsis(isLongHelp bool) string {
 				argName = name + "..."
 				isVariadic = true
 			}
+		case valueFlag:
+			if f.isVariadic() {
+				argName = name + "..."
+				isVariadic = true
+			}
 		}
 
 		if shouldBeOptional {
@@ -500,7 +524,7 @@ func (c *Cmd) generateSynopsis(isLongHelp bool) string {
 
 		// Stop after first variadic positional flag
 		if isVariadic {
-			sb.WriteString(" " + CyanS("[OPTIONS]"))
+			sb.WriteString(options)
 			return sb.String()
 		}
 	}
@@ -522,26 +546,34 @@ func (c *Cmd) generateSynopsis(isLongHelp bool) string {
 			isVariadic = f.Variadic
 		case *BoolSliceFlag:
 			isVariadic = f.Variadic
+		case valueFlag:
+			isVariadic = f.isVariadic()
 		}
 
 		if isVariadic {
 			// All variadic flags show as [name...]
 			sb.WriteString(" " + CyanS("[%s...]", name))
 			// Stop after first variadic flag
-			sb.WriteString(" " + CyanS("[OPTIONS]"))
+			sb.WriteString(options)
 			return sb.String()
 		} else {
-			// Non-variadic required flags show as <name>
+			// Non-variadic required flags show as <name>, or <name[=TYPE]> if
+			// their value is optional. The suffix is only valid syntax for the
+			// --name form, so flags that can be given positionally go without it.
+			argName := name
+			if _, optionalValue := getNoOptDefault(flag); optionalValue && getBaseFlag(flag).FlagOnly {
+				argName = fmt.Sprintf("%s[=%s]", name, getFlagType(flag))
+			}
 			shouldBeOptional := c.shouldFlagBeOptionalInSynopsis(flag)
 			if shouldBeOptional {
-				sb.WriteString(" " + CyanS("[%s]", name))
+				sb.WriteString(" " + CyanS("[%s]", argName))
 			} else {
-				sb.WriteString(" " + CyanS("<%s>", name))
+				sb.WriteString(" " + CyanS("<%s>", argName))
 			}
 		}
 	}
 
-	sb.WriteString(" " + CyanS("[OPTIONS]"))
+	sb.WriteString(options)
 	return sb.String()
 }
 
@@ -578,17 +610,19 @@ func (c *Cmd) formatFlags(flags []any, isLongHelp bool) string {
 			flagPart = fmt.Sprintf("  -%s", base.Short)
 		} else if base.Short != "" && base.Name != "" {
 			// Normal flag with both short and name
-			flagPart = fmt.Sprintf("  -%s, --%s", base.Short, base.Name)
+			flagPart = fmt.Sprintf("  -%s, %s", base.Short, c.longFlagNames(flag, base))
 		} else if base.Name != "" {
 			// Flag with only name (no short)
-			flagPart = fmt.Sprintf("      --%s", base.Name)
+			flagPart = fmt.Sprintf("      %s", c.longFlagNames(flag, base))
 		} else {
 			// Shouldn't happen - flag with neither name nor short
 			flagPart = fmt.Sprintf("  (unnamed flag)")
 		}
 
 		typeStr := getFlagType(flag)
-		if typeStr != "bool" {
+		if _, optionalValue := getNoOptDefault(flag); optionalValue && !base.PositionalOnly {
+			flagPart = fmt.Sprintf("%s[=%s]", flagPart, typeStr)
+		} else if typeStr != "bool" && typeStr != "" {
 			flagPart = fmt.Sprintf("%s %s", flagPart, typeStr)
 		}
 
@@ -616,8 +650,10 @@ func (c *Cmd) formatFlags(flags []any, isLongHelp bool) string {
 		hasUsage := base.Usage != ""
 		constraints := c.getConstraintString(flag)
 		hasConstraints := constraints != ""
+		_, isRepeatable := flag.(*CountFlag)
+		isDeprecated := base.Deprecated != ""
 
-		if hasUsage || hasConstraints {
+		if hasUsage || hasConstraints || isRepeatable || isDeprecated {
 			// Calculate padding to align descriptions
 			padding := maxWidth - len(flagPart)
 			if padding < 1 {
@@ -639,6 +675,8 @@ func (c *Cmd) formatFlags(flags []any, isLongHelp bool) string {
 				isVariadic = f.Variadic
 			case *BoolSliceFlag:
 				isVariadic = f.Variadic
+			case valueFlag:
+				isVariadic = f.isVariadic()
 			}
 
 			// Show status markers for non-variadic flags:
@@ -647,13 +685,26 @@ func (c *Cmd) formatFlags(flags []any, isLongHelp bool) string {
 			hasDefault := c.flagHasDefault(flag)
 			var shouldShowOptional bool
 			if base.PositionalOnly {
-				shouldShowOptional = base.Optional
+				shouldShowOptional = isOptional(base)
 			} else {
-				shouldShowOptional = base.Optional && !hasDefault
+				shouldShowOptional = isOptional(base) && !hasDefault
 			}
 
+			var markers []string
+			if isDeprecated {
+				markers = append(markers, "(deprecated)")
+			}
 			if shouldShowOptional && !isVariadic {
-				sb.WriteString("(optional) ")
+				markers = append(markers, "(optional)")
+			}
+			if isRepeatable {
+				markers = append(markers, "(repeatable)")
+			}
+			if len(markers) > 0 {
+				sb.WriteString(strings.Join(markers, " "))
+				if hasUsage || hasConstraints {
+					sb.WriteString(" ")
+				}
 			}
 
 			// Add usage text if it exists
@@ -686,11 +737,37 @@ func (c *Cmd) formatFlags(flags []any, isLongHelp bool) string {
 	return sb.String()
 }
 
+// getNoOptDefault returns the value a flag takes when given without one, and
+// whether its value is optional at all (see SetNoOptDefault).
+func getNoOptDefault(flag any) (string, bool) {
+	switch f := flag.(type) {
+	case *StringFlag:
+		if f.noOptDefault != nil {
+			return *f.noOptDefault, true
+		}
+	case *IntFlag:
+		if f.noOptDefault != nil {
+			return fmt.Sprintf("%d", *f.noOptDefault), true
+		}
+	}
+	return "", false
+}
+
 func isBoolFlag(flag any) bool {
 	_, ok := flag.(*BoolFlag)
 	return ok
 }
 
+// takesNoValue returns true for flags that never consume the next argument as
+// their value: bools and counts.
+func takesNoValue(flag any) bool {
+	switch flag.(type) {
+	case *BoolFlag, *CountFlag:
+		return true
+	}
+	return false
+}
+
 func getFlagType(flag any) string {
 	// Check for custom type override first
 	if base := getBaseFlag(flag); base != nil && base.CustomUsageType != "" {
@@ -733,10 +810,22 @@ func getFlagType(flag any) string {
 			return "[floats...]"
 		}
 		return "floats"
+	case valueFlag:
+		return f.usageType()
 	}
 	return ""
 }
 
+// longFlagNames renders a flag's long name followed by its aliases, e.g.
+// "--path, --dir".
+func (c *Cmd) longFlagNames(flag any, base *BaseFlag) string {
+	names := "--" + c.longFlagDisplayName(flag, base.Name)
+	for _, alias := range base.visibleAliases() {
+		names += ", --" + alias
+	}
+	return names
+}
+
 func (c *Cmd) getConstraintString(flag any) string {
 	var parts []string
 
@@ -754,18 +843,44 @@ func (c *Cmd) getConstraintString(flag any) string {
 		parts = append(parts, "Regex: "+regexStr)
 	}
 
+	keyEnumStr, keyRegexStr := c.getKeyConstraintStrings(flag)
+	if keyEnumStr != "" {
+		parts = append(parts, "Valid keys: "+keyEnumStr)
+	}
+	if keyRegexStr != "" {
+		parts = append(parts, "Key regex: "+keyRegexStr)
+	}
+
 	// Add separator for slices
 	if sepStr := c.getSeparatorString(flag); sepStr != "" {
 		parts = append(parts, "Separator: "+sepStr)
 	}
 
+	// Add value used when the flag is given without one
+	if noOptStr, ok := getNoOptDefault(flag); ok {
+		parts = append(parts, "Without value: "+noOptStr)
+	}
+
+	// Add environment variable binding
+	if base := getBaseFlag(flag); base != nil && base.Env != "" {
+		parts = append(parts, "Env: "+base.Env)
+	}
+
 	// Add relationship constraints
 	if reqStr := c.getRequiresString(flag); reqStr != "" {
 		parts = append(parts, "Requires: "+reqStr)
 	}
 
+	if requiredIfStr := c.getRequiredIfString(flag); requiredIfStr != "" {
+		parts = append(parts, "Required if: "+requiredIfStr)
+	}
+
+	exclParts := c.getExcludesIfStrings(flag)
 	if exclStr := c.getExcludesString(flag); exclStr != "" {
-		parts = append(parts, "Excludes: "+exclStr)
+		exclParts = append([]string{exclStr}, exclParts...)
+	}
+	if len(exclParts) > 0 {
+		parts = append(parts, "Excludes: "+strings.Join(exclParts, ", "))
 	}
 
 	// Join constraint parts with periods
@@ -841,6 +956,8 @@ func (c *Cmd) getDefaultString(flag any) string {
 			}
 			return fmt.Sprintf("[%s]", strings.Join(strs, ", "))
 		}
+	case valueFlag:
+		return f.defaultString()
 	}
 	return ""
 }
@@ -931,6 +1048,8 @@ func (c *Cmd) getRangeString(flag any) string {
 
 			return left + ", " + right
 		}
+	case valueFlag:
+		return f.rangeString()
 	}
 	return ""
 }
@@ -955,6 +1074,27 @@ func (c *Cmd) getRegexString(flag any) string {
 	return ""
 }
 
+// getKeyConstraintStrings returns the key enum and regex constraints of map flags.
+func (c *Cmd) getKeyConstraintStrings(flag any) (enumStr, regexStr string) {
+	switch f := flag.(type) {
+	case *StringMapFlag:
+		return formatKeyConstraints(f.KeyEnumConstraint, f.KeyRegexConstraint)
+	case *IntMapFlag:
+		return formatKeyConstraints(f.KeyEnumConstraint, f.KeyRegexConstraint)
+	}
+	return "", ""
+}
+
+func formatKeyConstraints(enum *[]string, regex *regexp.Regexp) (enumStr, regexStr string) {
+	if enum != nil && len(*enum) > 0 {
+		enumStr = fmt.Sprintf("[%s]", strings.Join(*enum, ", "))
+	}
+	if regex != nil {
+		regexStr = regex.String()
+	}
+	return enumStr, regexStr
+}
+
 func (c *Cmd) getSeparatorString(flag any) string {
 	switch f := flag.(type) {
 	case *StringSliceFlag:
@@ -977,6 +1117,10 @@ func (c *Cmd) getSeparatorString(flag any) string {
 		if f.Separator != nil {
 			return fmt.Sprintf("\"%s\"", *f.Separator)
 		}
+	case valueFlag:
+		if sep := f.separator(); sep != nil {
+			return fmt.Sprintf("\"%s\"", *sep)
+		}
 	}
 	return ""
 }
@@ -1023,10 +1167,12 @@ func (c *Cmd) shouldFlagBeOptionalInSynopsis(flag any) bool {
 		hasDefault = f.Default != nil
 	case *BoolSliceFlag:
 		hasDefault = f.Default != nil
+	case valueFlag:
+		hasDefault = f.hasDefault()
 	}
 
 	// Flag is optional if it has a default OR was explicitly set optional
-	if hasDefault || base.Optional {
+	if hasDefault || isOptional(base) {
 		return true
 	}
 
@@ -1055,6 +1201,8 @@ func (c *Cmd) flagHasDefault(flag any) bool {
 		return f.Default != nil
 	case *BoolSliceFlag:
 		return true // Boolean slice flags have implicit default of empty slice
+	case valueFlag:
+		return f.hasDefault()
 	}
 	return false
 }
@@ -1,9 +1,11 @@
 package ra
 
 import (
+	"net/netip"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/amterp/color"
 
@@ -2085,3 +2087,407 @@ Commands:
 	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
 	assert.NotContains(t, usage, "[subcommand]")
 }
+
+func Test_Usage_EnvBinding(t *testing.T) {
+	cmd := NewCmd("deploy")
+
+	_, err := NewInt("port").
+		SetUsage("Port to listen on").
+		SetDefault(8080).
+		SetEnv("DEPLOY_PORT").
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewStringSlice("tags").
+		SetSeparator(",").
+		SetOptional(true).
+		SetEnv("DEPLOY_TAGS").
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  deploy [port] [tags] [OPTIONS]
+
+Arguments:
+      --port int    Port to listen on. Env: DEPLOY_PORT (default 8080)
+      --tags strs   (optional) Separator: ",". Env: DEPLOY_TAGS
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_CustomFlags(t *testing.T) {
+	cmd := NewCmd("net")
+
+	_, err := NewCustom("prefix", netip.ParsePrefix, netip.Prefix.String).
+		SetUsage("Network to scan").
+		SetDefault(netip.MustParsePrefix("10.0.0.0/8")).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewCustomSlice("exclude", netip.ParseAddr, netip.Addr.String).
+		SetSeparator(",").
+		SetDefault([]netip.Addr{netip.MustParseAddr("10.0.0.1")}).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewCustomSlice("hosts", netip.ParseAddr, netip.Addr.String).
+		SetCustomUsageType("ips").
+		SetVariadic(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  net [prefix] [hosts...] [OPTIONS]
+
+Arguments:
+      --prefix prefix   Network to scan. (default 10.0.0.0/8)
+      --hosts ips
+      --exclude addrs   Separator: "," (default [10.0.0.1])
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_DurationAndTimeFlags(t *testing.T) {
+	cmd := NewCmd("poll")
+
+	_, err := NewDuration("interval").
+		SetUsage("Time between polls").
+		SetDefault(time.Minute).
+		SetMin(time.Second, true).
+		SetMax(time.Hour, false).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewDurationSlice("backoff").
+		SetSeparator(",").
+		SetDefault([]time.Duration{time.Second, 90 * time.Second}).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewTime("since", time.DateOnly).
+		SetUsage("Only events after this day").
+		SetMin(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), true).
+		SetOptional(true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  poll [interval] [backoff] [OPTIONS]
+
+Arguments:
+      --interval duration   Time between polls. Range: [1s, 1h) (default 1m)
+      --backoff durations   Separator: "," (default [1s, 1m30s])
+      --since time          (optional) Only events after this day. Range: [2020-01-01, )
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_SizedNumberFlags(t *testing.T) {
+	cmd := NewCmd("serve")
+
+	_, err := NewUint16("port").
+		SetUsage("Port to listen on").
+		SetDefault(8080).
+		SetMin(1, true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewFloat32("ratio").
+		SetDefault(0.1).
+		SetMax(1, true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewUint8Slice("bytes").
+		SetVariadic(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  serve [bytes...] [OPTIONS]
+
+Arguments:
+      --bytes [uint8s...]
+      --port uint16         Port to listen on. Range: [1, ) (default 8080)
+      --ratio float32       Range: (, 1] (default 0.1)
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_ByteSizeFlag(t *testing.T) {
+	cmd := NewCmd("cache")
+
+	_, err := NewByteSize("max-size").
+		SetUsage("Cache size limit").
+		SetDefault(512*MiB).
+		SetMin(MiB, true).
+		SetMax(10*GiB, true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  cache [max-size] [OPTIONS]
+
+Arguments:
+      --max-size size   Cache size limit. Range: [1MiB, 10GiB] (default 512MiB)
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_MapFlags(t *testing.T) {
+	cmd := NewCmd("deploy")
+
+	_, err := NewStringMap("label").
+		SetUsage("Labels to apply").
+		SetKeyRegexConstraint(regexp.MustCompile(`^[a-z]+$`)).
+		SetOptional(true).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	_, err = NewIntMap("limit").
+		SetKeyEnumConstraint([]string{"cpu", "mem"}).
+		SetDefault(map[string]int{"mem": 512, "cpu": 1}).
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  deploy [limit] [OPTIONS]
+
+Arguments:
+      --label key=str   (optional) Labels to apply. Key regex: ^[a-z]+$. Separator: ","
+      --limit key=int   Valid keys: [cpu, mem]. Separator: "," (default [cpu=1, mem=512])
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_CountFlag(t *testing.T) {
+	cmd := NewCmd("tool")
+
+	_, err := NewCount("verbose").SetShort("v").SetUsage("Increase verbosity").SetMax(3).Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewCount("quiet").SetShort("q").Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  tool [OPTIONS]
+
+Arguments:
+  -v, --verbose   (repeatable) Increase verbosity. Range: (, 3]
+  -q, --quiet     (repeatable)
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_NegatableBool(t *testing.T) {
+	cmd := NewCmd("tool")
+
+	_, err := NewBool("color").SetShort("c").SetDefault(true).SetNegatable(true).SetUsage("Colorize output").Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewBool("pager").SetNegatable(true).SetUsage("Page output").Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  tool [OPTIONS]
+
+Arguments:
+  -c, --[no-]color   Colorize output. (default true)
+      --[no-]pager   Page output
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_NoOptDefault(t *testing.T) {
+	cmd := NewCmd("tool")
+
+	_, err := NewString("color").
+		SetUsage("When to colorize").
+		SetDefault("auto").
+		SetNoOptDefault("always").
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewString("profile").
+		SetNoOptDefault("profile.out").
+		SetFlagOnly(true).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  tool [color[=str]] <profile[=str]> [OPTIONS]
+
+Arguments:
+      --color[=str]     When to colorize. Without value: always (default auto)
+      --profile[=str]   Without value: profile.out
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_NoOptDefault_Positional(t *testing.T) {
+	cmd := NewCmd("tool")
+
+	_, err := NewString("level").
+		SetUsage("Log level").
+		SetNoOptDefault("debug").
+		Register(cmd)
+	assert.NoError(t, err)
+	_, err = NewInt("depth").
+		SetDefault(1).
+		SetNoOptDefault(3).
+		Register(cmd)
+	assert.NoError(t, err)
+
+	usage := cmd.GenerateUsage(false)
+	expected := `Usage:
+  tool <level> [depth] [OPTIONS]
+
+Arguments:
+      --level[=str]   Log level. Without value: debug
+      --depth[=int]   Without value: 3 (default 1)
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_Aliases(t *testing.T) {
+	root := NewCmd("tool")
+	_, err := NewString("path").SetShort("p").SetAliases("dir").SetUsage("Where to look").SetFlagOnly(true).SetOptional(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewBool("force").SetAliases("yes").SetUsage("Skip prompts").Register(root)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("remove").SetAliases("rm", "del").SetDescription("Remove things"))
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("add").SetDescription("Add things"))
+	assert.NoError(t, err)
+
+	usage := root.GenerateUsage(false)
+	expected := `Usage:
+  tool [subcommand] [OPTIONS]
+
+Commands:
+  add               Add things
+  remove, rm, del   Remove things
+
+Arguments:
+  -p, --path, --dir str   (optional) Where to look
+      --force, --yes      Skip prompts
+`
+
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_Deprecated(t *testing.T) {
+	root := NewCmd("tool")
+	_, err := NewString("output").SetDeprecatedAlias("dest", "use --output instead").SetUsage("Where to write").SetFlagOnly(true).SetOptional(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("out").SetDeprecated("use --output instead").SetUsage("Where to write").SetFlagOnly(true).SetOptional(true).Register(root)
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("purge").SetDeprecated("use 'remove' instead").SetDescription("Remove everything"))
+	assert.NoError(t, err)
+	_, err = root.RegisterCmd(NewCmd("remove").SetDescription("Remove things"))
+	assert.NoError(t, err)
+
+	usage := root.GenerateUsage(false)
+	expected := `Usage:
+  tool [subcommand] [OPTIONS]
+
+Commands:
+  remove   Remove things
+
+Arguments:
+      --output str   (optional) Where to write
+`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+
+	usage = root.GenerateUsage(true)
+	expected = `Usage:
+  tool [subcommand] [OPTIONS]
+
+Commands:
+  purge    (deprecated) Remove everything
+  remove   Remove things
+
+Arguments:
+      --output str   (optional) Where to write
+      --out str      (deprecated) (optional) Where to write
+`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_FlagGroups(t *testing.T) {
+	root := NewCmd("fetch")
+	_, err := NewString("out").SetUsage("Where to write").SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("file").SetUsage("Read from a file").SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("url").SetUsage("Read from a URL").SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewBool("json").SetUsage("Print JSON").Register(root)
+	assert.NoError(t, err)
+	_, err = NewBool("yaml").SetUsage("Print YAML").Register(root)
+	assert.NoError(t, err)
+	root.AddFlagGroup(ExactlyOne("file", "url")).AddFlagGroup(AtMostOne("json", "yaml"))
+
+	usage := root.GenerateUsage(false)
+	expected := `Usage:
+  fetch <out> {--file | --url} [--json | --yaml] [OPTIONS]
+
+Arguments:
+      --out str    Where to write
+      --file str   Read from a file
+      --url str    Read from a URL
+      --json       Print JSON
+      --yaml       Print YAML
+`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
+
+func Test_Usage_ConditionalConstraints(t *testing.T) {
+	root := NewCmd("connect")
+	_, err := NewString("mode").SetUsage("How to connect").SetDefault("local").SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("host").SetUsage("Host to connect to").SetRequiredIf("mode", "remote").SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewInt("port").SetUsage("Port").SetExcludesIf([]string{"socket"}, "mode", "remote").SetOptional(true).SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+	_, err = NewString("socket").SetUsage("Socket path").SetOptional(true).SetFlagOnly(true).Register(root)
+	assert.NoError(t, err)
+
+	usage := root.GenerateUsage(true)
+	expected := `Usage:
+  connect [mode] [OPTIONS]
+
+Arguments:
+      --mode str     How to connect. (default local)
+      --host str     (optional) Host to connect to. Required if: mode=remote
+      --port int     (optional) Port. Excludes: socket (if mode=remote)
+      --socket str   (optional) Socket path
+`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(usage))
+}
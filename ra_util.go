@@ -26,6 +26,8 @@ func getBaseFlag(flag any) *BaseFlag {
 		return &f.BaseFlag
 	case *BoolSliceFlag:
 		return &f.BaseFlag
+	case valueFlag:
+		return f.getBase()
 	}
 	return nil
 }
@@ -62,6 +64,107 @@ func deepCopyFlag(flag any) any {
 	case *BoolSliceFlag:
 		copy := *f
 		return &copy
+	case valueFlag:
+		return f.copyFlag()
+	}
+	return nil
+}
+
+// cloneFlag copies flag for a parse that mustn't touch it, with a Value pointer of
+// its own. clones maps originals to their copies, so that a flag shared between
+// commands, such as a global flag, or a Value shared between flags stays shared.
+func cloneFlag(flag any, clones map[any]any) any {
+	if clone, ok := clones[flag]; ok {
+		return clone
+	}
+	var clone any
+	switch f := flag.(type) {
+	case *BoolFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *StringFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *IntFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *Int64Flag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *Float64Flag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *StringSliceFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *IntSliceFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *Int64SliceFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *Float64SliceFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case *BoolSliceFlag:
+		copy := *f
+		copy.Value = cloneValue(f.Value, clones)
+		clone = &copy
+	case valueFlag:
+		clone = f.cloneWith(clones)
+	}
+	clones[flag] = clone
+	return clone
+}
+
+// cloneValue returns a new pointer standing in for v in a cloned command tree, the
+// same one for every flag that shared v.
+func cloneValue[T any](v *T, clones map[any]any) *T {
+	if v == nil {
+		return nil
+	}
+	if clone, ok := clones[v]; ok {
+		return clone.(*T)
+	}
+	clone := new(T)
+	clones[v] = clone
+	return clone
+}
+
+// flagValuePtr returns the Value pointer of flag, e.g. *int for an IntFlag.
+func flagValuePtr(flag any) any {
+	switch f := flag.(type) {
+	case *BoolFlag:
+		return f.Value
+	case *StringFlag:
+		return f.Value
+	case *IntFlag:
+		return f.Value
+	case *Int64Flag:
+		return f.Value
+	case *Float64Flag:
+		return f.Value
+	case *StringSliceFlag:
+		return f.Value
+	case *IntSliceFlag:
+		return f.Value
+	case *Int64SliceFlag:
+		return f.Value
+	case *Float64SliceFlag:
+		return f.Value
+	case *BoolSliceFlag:
+		return f.Value
+	case valueFlag:
+		return f.valuePtr()
 	}
 	return nil
 }
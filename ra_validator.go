@@ -0,0 +1,31 @@
+package ra
+
+import (
+	"fmt"
+)
+
+// validateValue runs a flag's custom validator, if set, on a value parsed from
+// raw, reporting failure like the built-in constraint errors.
+func validateValue[T any](name string, validator func(T) error, value T, raw string) error {
+	if validator == nil {
+		return nil
+	}
+	if err := validator(value); err != nil {
+		return newParseError(ConstraintViolation, name, raw, "Invalid '%s' value: %s (%w)", name, raw, err)
+	}
+	return nil
+}
+
+// validateDefaults runs a flag's custom validator, if set, on its default values
+// at register time.
+func validateDefaults[T any](name string, validator func(T) error, values ...T) error {
+	if validator == nil {
+		return nil
+	}
+	for _, value := range values {
+		if err := validator(value); err != nil {
+			return fmt.Errorf("invalid default value for flag %q: %w", name, err)
+		}
+	}
+	return nil
+}